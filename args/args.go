@@ -8,13 +8,29 @@ import (
 )
 
 var (
-	FakesDir string
-	Select   []string
+	FakesDir   string
+	Select     []string
+	Template   string
+	FromStruct string
+
+	ExpandAliases bool
+	LocalTests    bool
+
+	Tags   []string
+	GOOS   string
+	GOARCH string
 )
 
 func init() {
 	flag.StringVarP(&FakesDir, "fake-dir", "d", "", "the directory to create the mocks package in. If unset, it will default to 'path/fake")
 	flag.StringArrayVarP(&Select, "select", "s", nil, "specify which interfaces to generate mocks for. Can be a comma separated list or used repeatedly.")
+	flag.StringVarP(&Template, "template", "t", "", "a text/template file to render each fake with instead of the built-in table-mocks fake. Start from templates/stub.tmpl.")
+	flag.StringVar(&FromStruct, "from-struct", "", "extract an interface from the exported methods of this struct type, including the ones promoted from structs of the package it embeds, declare it next to the struct and generate its mock.")
+	flag.BoolVar(&ExpandAliases, "expand-aliases", false, "refer to the target types of type aliases instead of to the aliases.")
+	flag.StringSliceVar(&Tags, "tags", nil, "build tags to satisfy when reading the package. Can be a comma separated list or used repeatedly.")
+	flag.StringVar(&GOOS, "goos", "", "read the package's files for this GOOS instead of the current one.")
+	flag.StringVar(&GOARCH, "goarch", "", "read the package's files for this GOARCH instead of the current one.")
+	flag.BoolVar(&LocalTests, "local-tests", true, "write the mocks of unexported interfaces, which are generated into their own package, to _test.go files.")
 }
 
 func Parse() (string, error) {
@@ -0,0 +1,62 @@
+// Package tbtest provides a testing.TB that records the failures it's given
+// instead of reporting them, for tests of code that fails tests.
+package tbtest
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+// TB captures failures instead of reporting them so that tests can check
+// what would have been reported. Errors and Fatals hold the messages of the
+// Errorf and Fatalf calls.
+type TB struct {
+	testing.TB
+
+	mutex    sync.Mutex
+	Errors   []string
+	Fatals   []string
+	cleanups []func()
+}
+
+// New returns a TB recording the failures of a test run by t.
+func New(t testing.TB) *TB {
+	return &TB{TB: t}
+}
+
+func (r *TB) Helper() {}
+
+func (r *TB) Errorf(format string, args ...interface{}) {
+	r.mutex.Lock()
+	r.Errors = append(r.Errors, fmt.Sprintf(format, args...))
+	r.mutex.Unlock()
+}
+
+func (r *TB) Fatalf(format string, args ...interface{}) {
+	r.mutex.Lock()
+	r.Fatals = append(r.Fatals, fmt.Sprintf(format, args...))
+	r.mutex.Unlock()
+	runtime.Goexit()
+}
+
+func (r *TB) Cleanup(fn func()) {
+	r.cleanups = append(r.cleanups, fn)
+}
+
+// Run calls fn on a new goroutine, the way the testing package runs a test,
+// so that Fatalf can stop it. Cleanups run once fn returns.
+func (r *TB) Run(fn func(testing.TB)) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer func() {
+			for i := len(r.cleanups) - 1; i >= 0; i-- {
+				r.cleanups[i]()
+			}
+		}()
+		fn(r)
+	}()
+	<-done
+}
@@ -1,9 +1,12 @@
 package main
 
 import (
+	"bytes"
+	"io/ioutil"
 	"log"
 	"os"
 	"path/filepath"
+	"text/template"
 
 	"github.com/serenize/snaker"
 
@@ -17,23 +20,102 @@ func main() {
 		log.Fatal(err)
 	}
 
-	m := mock.ReadPkg(filepath.Dir(path), args.Select)
+	dir := filepath.Dir(path)
+	var m *mock.Mock
+	if args.FromStruct != "" {
+		var ext *mock.Extracted
+		m, ext, err = mock.ReadStruct(dir, args.FromStruct, readOptions()...)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := writeExtracted(dir, ext); err != nil {
+			log.Fatal(err)
+		}
+	} else {
+		m, err = mock.ReadPkg(dir, args.Select, readOptions()...)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
 
 	if err := os.MkdirAll(args.FakesDir, 0755); err != nil {
 		log.Fatal(err)
 	}
 
+	tmpl, err := parseTemplate(args.Template)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	for _, ifce := range m.Interfaces {
 		fileName := filepath.Join(args.FakesDir, snaker.CamelToSnake(ifce.Name)+".go")
+		pkg := "fake"
+		if ifce.Local() {
+			fileName, pkg = localFileName(dir, ifce.Name), ifce.Package
+		}
 
-		iFile, err := os.Create(fileName)
-		if err != nil {
+		// Generate first so that a fake that can't be generated leaves no
+		// empty file behind.
+		buf := new(bytes.Buffer)
+		if err := mock.GenerateTemplate(tmpl, &ifce, pkg, buf); err != nil {
 			log.Fatal(err)
 		}
-		defer iFile.Close()
-
-		if err := mock.GenerateFile(&ifce, "fake", iFile); err != nil {
+		if err := ioutil.WriteFile(fileName, buf.Bytes(), 0644); err != nil {
 			log.Fatal(err)
 		}
 	}
 }
+
+// localFileName names the file in dir holding the fake of an interface that
+// has to be generated into its own package, like zz_fake_storer_test.go.
+func localFileName(dir, name string) string {
+	fileName := "zz_fake_" + snaker.CamelToSnake(name)
+	if args.LocalTests {
+		fileName += "_test"
+	}
+	return filepath.Join(dir, fileName+".go")
+}
+
+// readOptions returns the options to read the package with.
+func readOptions() []mock.ReadOption {
+	var opts []mock.ReadOption
+	if args.ExpandAliases {
+		opts = append(opts, mock.ExpandAliases())
+	}
+	if len(args.Tags) > 0 {
+		opts = append(opts, mock.BuildTags(args.Tags...))
+	}
+	if args.GOOS != "" {
+		opts = append(opts, mock.GOOS(args.GOOS))
+	}
+	if args.GOARCH != "" {
+		opts = append(opts, mock.GOARCH(args.GOARCH))
+	}
+	return opts
+}
+
+// parseTemplate parses the template file at path, or the built-in table-mocks
+// template when path is empty.
+func parseTemplate(path string) (*template.Template, error) {
+	if path == "" {
+		return mock.ParseTemplate("table-mocks", mock.DefaultTemplate)
+	}
+
+	text, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return mock.ParseTemplate(filepath.Base(path), string(text))
+}
+
+// writeExtracted declares the interface extracted from a struct in a file next
+// to the struct.
+func writeExtracted(dir string, ext *mock.Extracted) error {
+	file, err := os.Create(filepath.Join(dir, snaker.CamelToSnake(ext.Struct)+"_interface.go"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return mock.GenerateExtracted(ext, file)
+}
@@ -0,0 +1,170 @@
+// Package match provides argument matchers for the conditional returns on
+// fakes generated by table-mocks.
+package match
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Matcher reports whether a single argument value is acceptable.
+type Matcher interface {
+	Match(v interface{}) bool
+	String() string
+}
+
+// Args reports whether every matcher accepts the argument in the same
+// position. Arguments without a matcher are accepted, so a rule only needs to
+// list the leading arguments it cares about.
+func Args(matchers []Matcher, args ...interface{}) bool {
+	if len(matchers) > len(args) {
+		return false
+	}
+	for i, m := range matchers {
+		if !m.Match(args[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+type matcher struct {
+	desc  string
+	match func(interface{}) bool
+}
+
+func (m matcher) Match(v interface{}) bool { return m.match(v) }
+func (m matcher) String() string           { return m.desc }
+
+// Eq matches values that are reflect.DeepEqual to want.
+func Eq(want interface{}) Matcher {
+	return matcher{
+		desc:  fmt.Sprintf("is equal to %#v", want),
+		match: func(v interface{}) bool { return reflect.DeepEqual(want, v) },
+	}
+}
+
+// Any matches every value.
+func Any() Matcher {
+	return matcher{
+		desc:  "is anything",
+		match: func(interface{}) bool { return true },
+	}
+}
+
+// Nil matches untyped nil and nil pointers, maps, slices, chans, funcs and
+// interfaces.
+func Nil() Matcher {
+	return matcher{
+		desc: "is nil",
+		match: func(v interface{}) bool {
+			if v == nil {
+				return true
+			}
+			switch rv := reflect.ValueOf(v); rv.Kind() {
+			case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Ptr, reflect.Slice:
+				return rv.IsNil()
+			}
+			return false
+		},
+	}
+}
+
+// Not inverts m.
+func Not(m Matcher) Matcher {
+	return matcher{
+		desc:  "not " + m.String(),
+		match: func(v interface{}) bool { return !m.Match(v) },
+	}
+}
+
+// All matches values accepted by every one of ms.
+func All(ms ...Matcher) Matcher {
+	return matcher{
+		desc: join(ms, " and "),
+		match: func(v interface{}) bool {
+			for _, m := range ms {
+				if !m.Match(v) {
+					return false
+				}
+			}
+			return true
+		},
+	}
+}
+
+// OneOf matches values accepted by at least one of ms.
+func OneOf(ms ...Matcher) Matcher {
+	return matcher{
+		desc: join(ms, " or "),
+		match: func(v interface{}) bool {
+			for _, m := range ms {
+				if m.Match(v) {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
+
+// Func matches values for which fn returns true. The desc is used when the
+// matcher is printed.
+func Func(desc string, fn func(interface{}) bool) Matcher {
+	return matcher{desc: desc, match: fn}
+}
+
+// Regex matches strings, byte slices and fmt.Stringers against the regular
+// expression pattern. It panics if pattern does not compile.
+func Regex(pattern string) Matcher {
+	re := regexp.MustCompile(pattern)
+
+	return matcher{
+		desc: fmt.Sprintf("matches %q", pattern),
+		match: func(v interface{}) bool {
+			switch s := v.(type) {
+			case string:
+				return re.MatchString(s)
+			case []byte:
+				return re.Match(s)
+			case fmt.Stringer:
+				return re.MatchString(s.String())
+			}
+			return false
+		},
+	}
+}
+
+// Len matches strings, arrays, slices, maps and chans of length n.
+func Len(n int) Matcher {
+	return matcher{
+		desc: fmt.Sprintf("has length %d", n),
+		match: func(v interface{}) bool {
+			switch rv := reflect.ValueOf(v); rv.Kind() {
+			case reflect.Array, reflect.Chan, reflect.Map, reflect.Slice, reflect.String:
+				return rv.Len() == n
+			}
+			return false
+		},
+	}
+}
+
+// TypeOf matches values with the same dynamic type as example.
+func TypeOf(example interface{}) Matcher {
+	typ := reflect.TypeOf(example)
+
+	return matcher{
+		desc:  fmt.Sprintf("is of type %v", typ),
+		match: func(v interface{}) bool { return reflect.TypeOf(v) == typ },
+	}
+}
+
+func join(ms []Matcher, sep string) string {
+	descs := make([]string, len(ms))
+	for i, m := range ms {
+		descs[i] = m.String()
+	}
+	return "(" + strings.Join(descs, sep) + ")"
+}
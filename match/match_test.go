@@ -0,0 +1,125 @@
+package match_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/vitreuz/table-mocks/match"
+)
+
+func TestMatchers(t *testing.T) {
+	var nilErr error
+	var nilSlice []string
+
+	tests := [...]struct {
+		name    string
+		matcher Matcher
+		accepts []interface{}
+		rejects []interface{}
+		desc    string
+	}{
+		{
+			"Eq",
+			Eq("abc"),
+			[]interface{}{"abc"},
+			[]interface{}{"abd", 1, nil},
+			`is equal to "abc"`,
+		}, {
+			"Eq deep",
+			Eq([]int{1, 2}),
+			[]interface{}{[]int{1, 2}},
+			[]interface{}{[]int{2, 1}, []int(nil)},
+			"is equal to []int{1, 2}",
+		}, {
+			"Any",
+			Any(),
+			[]interface{}{1, "a", nil},
+			nil,
+			"is anything",
+		}, {
+			"Nil",
+			Nil(),
+			[]interface{}{nil, nilErr, nilSlice, (*int)(nil)},
+			[]interface{}{0, "", errors.New("err")},
+			"is nil",
+		}, {
+			"Not",
+			Not(Eq(1)),
+			[]interface{}{2, "1"},
+			[]interface{}{1},
+			"not is equal to 1",
+		}, {
+			"All",
+			All(Regex("^a"), Len(3)),
+			[]interface{}{"abc"},
+			[]interface{}{"ab", "bcd"},
+			`(matches "^a" and has length 3)`,
+		}, {
+			"OneOf",
+			OneOf(Eq(1), Eq(2)),
+			[]interface{}{1, 2},
+			[]interface{}{3},
+			"(is equal to 1 or is equal to 2)",
+		}, {
+			"Func",
+			Func("is positive", func(v interface{}) bool { n, ok := v.(int); return ok && n > 0 }),
+			[]interface{}{1},
+			[]interface{}{0, "1"},
+			"is positive",
+		}, {
+			"Regex",
+			Regex(`^\d+s$`),
+			[]interface{}{"10s", []byte("1s"), 5 * time.Second},
+			[]interface{}{"10", 10},
+			`matches "^\\d+s$"`,
+		}, {
+			"TypeOf",
+			TypeOf(""),
+			[]interface{}{"", "a"},
+			[]interface{}{1, nil},
+			"is of type string",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, v := range tt.accepts {
+				if !tt.matcher.Match(v) {
+					t.Errorf("expected %q to accept %#v", tt.matcher, v)
+				}
+			}
+			for _, v := range tt.rejects {
+				if tt.matcher.Match(v) {
+					t.Errorf("expected %q to reject %#v", tt.matcher, v)
+				}
+			}
+			if desc := tt.matcher.String(); desc != tt.desc {
+				t.Errorf("expected description %q but got %q", tt.desc, desc)
+			}
+		})
+	}
+}
+
+func TestArgs(t *testing.T) {
+	tests := [...]struct {
+		name     string
+		matchers []Matcher
+		args     []interface{}
+		expect   bool
+	}{
+		{"No matchers", nil, []interface{}{1, 2}, true},
+		{"All match", []Matcher{Eq(1), Eq(2)}, []interface{}{1, 2}, true},
+		{"Leading match", []Matcher{Eq(1)}, []interface{}{1, 2}, true},
+		{"Mismatch", []Matcher{Eq(1), Eq(3)}, []interface{}{1, 2}, false},
+		{"Too many matchers", []Matcher{Any(), Any()}, []interface{}{1}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Args(tt.matchers, tt.args...); got != tt.expect {
+				t.Errorf("expected %t but got %t", tt.expect, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package mock
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+const runtimeImport = "github.com/vitreuz/table-mocks/tablemock"
+
+// generateAsserts returns the testing.TB assertion helpers for a single
+// method. Each helper reads the recorded calls under the method's read lock and
+// hands the comparison off to the tablemock package.
+func (meth Method) generateAsserts(ifce Interface) []ast.Decl {
+	return []ast.Decl{
+		meth.generateAssertCalls(ifce, "Called", "AssertCalled"),
+		meth.generateAssertCalledTimes(ifce),
+		meth.generateAssertCalledWith(ifce),
+		meth.generateAssertCalls(ifce, "NotCalled", "AssertNotCalled"),
+	}
+}
+
+func (meth Method) assertName(ifce Interface) *ast.BasicLit {
+	return stringLit(ifce.Name + "." + meth.Name)
+}
+
+func (meth Method) assertPreamble(read ...ast.Stmt) []ast.Stmt {
+	fakeMethodMutex := selectorExpr(ast.NewIdent("fake"), meth.mutexName())
+
+	stmts := []ast.Stmt{
+		exprStmt(call(selectorExpr(ast.NewIdent("t"), "Helper"))),
+		exprStmt(call(selectorExpr(fakeMethodMutex, "RLock"))),
+	}
+	stmts = append(stmts, read...)
+	stmts = append(stmts, &ast.AssignStmt{
+		Lhs: expression(ast.NewIdent("calls")),
+		Rhs: expression(selectorExpr(ast.NewIdent("fake"), meth.callsName())),
+		Tok: token.DEFINE,
+	})
+
+	return append(stmts, exprStmt(call(selectorExpr(fakeMethodMutex, "RUnlock"))))
+}
+
+func (meth Method) generateAssertCalls(ifce Interface, suffix, assert string) *ast.FuncDecl {
+	body := blockStmt(meth.assertPreamble()...)
+	body.List = append(body.List, exprStmt(call(
+		selectorExpr(ast.NewIdent("tablemock"), assert),
+		ast.NewIdent("t"), meth.assertName(ifce), ast.NewIdent("calls"),
+	)))
+
+	recv := ifce.recv()
+	funcName := "Assert" + strings.Title(meth.Name) + suffix
+	params := fieldList(field(selectorExpr(ast.NewIdent("testing"), "TB"), "t"))
+
+	return funcDecl(recv, funcName, params, fieldList(), body)
+}
+
+func (meth Method) generateAssertCalledTimes(ifce Interface) *ast.FuncDecl {
+	body := blockStmt(meth.assertPreamble()...)
+	body.List = append(body.List, exprStmt(call(
+		selectorExpr(ast.NewIdent("tablemock"), "AssertCalledTimes"),
+		ast.NewIdent("t"), meth.assertName(ifce), ast.NewIdent("times"), ast.NewIdent("calls"),
+	)))
+
+	recv := ifce.recv()
+	funcName := "Assert" + strings.Title(meth.Name) + "CalledTimes"
+	params := fieldList(
+		field(selectorExpr(ast.NewIdent("testing"), "TB"), "t"),
+		field(ast.NewIdent("int"), "times"),
+	)
+
+	return funcDecl(recv, funcName, params, fieldList(), body)
+}
+
+func (meth Method) generateAssertCalledWith(ifce Interface) *ast.FuncDecl {
+	fakeMethod := ast.NewIdent("fakeMethod")
+	empty := ast.NewIdent("interface{}")
+
+	names := sliceLit(ast.NewIdent("string"))
+	want := sliceLit(empty)
+	got := sliceLit(empty)
+	params := fieldList(
+		field(selectorExpr(ast.NewIdent("testing"), "TB"), "t"),
+		field(ast.NewIdent("int"), "call"),
+	)
+	for _, arg := range meth.Args {
+		params.List = append(params.List, arg.variable())
+		names.Elts = append(names.Elts, stringLit(arg.Name))
+		want.Elts = append(want.Elts, ast.NewIdent(arg.argName()))
+		got.Elts = append(got.Elts, selectorExpr(fakeMethod, arg.fieldName()))
+	}
+
+	// A method without args has nothing to read back from the record.
+	var read []ast.Stmt
+	if len(meth.Args) > 0 {
+		read = append(read, &ast.AssignStmt{
+			Lhs: expression(fakeMethod),
+			Rhs: expression(valueIndex(selectorExpr(ast.NewIdent("fake"), meth.recordName()), "call")),
+			Tok: token.DEFINE,
+		})
+	}
+	body := blockStmt(meth.assertPreamble(read...)...)
+	body.List = append(body.List, exprStmt(call(
+		selectorExpr(ast.NewIdent("tablemock"), "AssertCalledWith"),
+		ast.NewIdent("t"), meth.assertName(ifce), ast.NewIdent("call"), ast.NewIdent("calls"),
+		names, want, got,
+	)))
+
+	recv := ifce.recv()
+	funcName := "Assert" + strings.Title(meth.Name) + "CalledWith"
+
+	return funcDecl(recv, funcName, params, fieldList(), body)
+}
@@ -3,14 +3,15 @@ package mock
 import (
 	"go/ast"
 	"go/token"
+	"strconv"
 )
 
 func call(fn ast.Expr, args ...ast.Expr) *ast.CallExpr {
 	return &ast.CallExpr{Fun: fn, Args: args}
 }
 
-func compositeLit(name string, deref bool) *ast.UnaryExpr {
-	expr := &ast.UnaryExpr{X: &ast.CompositeLit{Type: ast.NewIdent(name)}}
+func compositeLit(typ ast.Expr, deref bool) *ast.UnaryExpr {
+	expr := &ast.UnaryExpr{X: &ast.CompositeLit{Type: typ}}
 	if deref {
 		expr.Op = token.AND
 	}
@@ -42,6 +43,9 @@ func funcDecl(recv *ast.Field, name string, params, results *ast.FieldList, body
 		Type: &ast.FuncType{Params: params, Results: results},
 		Body: body,
 	}
+	if n := len(body.List); n > 1 {
+		body.List[n-1] = spaced(body.List[n-1]).(ast.Stmt)
+	}
 	if recv != nil {
 		decl.Recv = &ast.FieldList{List: []*ast.Field{recv}}
 	}
@@ -53,10 +57,6 @@ func selectorExpr(x ast.Expr, sel string) *ast.SelectorExpr {
 	return &ast.SelectorExpr{X: x, Sel: ast.NewIdent(sel)}
 }
 
-func simpleMap(key, value string) *ast.MapType {
-	return &ast.MapType{Key: ast.NewIdent(key), Value: ast.NewIdent(value)}
-}
-
 func starExpr(name string) *ast.StarExpr {
 	return &ast.StarExpr{X: ast.NewIdent(name)}
 }
@@ -68,3 +68,19 @@ func blockStmt(stmts ...ast.Stmt) *ast.BlockStmt {
 func valueIndex(x ast.Expr, val string) *ast.IndexExpr {
 	return &ast.IndexExpr{X: x, Index: &ast.BasicLit{Value: val}}
 }
+
+func stringLit(val string) *ast.BasicLit {
+	return &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(val)}
+}
+
+func sliceLit(typ ast.Expr, elts ...ast.Expr) *ast.CompositeLit {
+	return &ast.CompositeLit{Type: &ast.ArrayType{Elt: typ}, Elts: elts}
+}
+
+func exprStmt(expr ast.Expr) *ast.ExprStmt {
+	return &ast.ExprStmt{X: expr}
+}
+
+func assign(lhs, rhs ast.Expr) *ast.AssignStmt {
+	return &ast.AssignStmt{Lhs: expression(lhs), Rhs: expression(rhs), Tok: token.ASSIGN}
+}
@@ -0,0 +1,51 @@
+package mock
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// generateCalls returns the Calls method, named by helperName, which lists the
+// calls made on this fake in the order they happened across all of its
+// methods.
+func (ifce Interface) generateCalls() *ast.FuncDecl {
+	recorder := selectorExpr(selectorExpr(ast.NewIdent("fake"), "opts"), "Recorder")
+	body := blockStmt(&ast.ReturnStmt{
+		Results: expression(call(selectorExpr(recorder, "CallsFor"), ast.NewIdent("fake"))),
+	})
+
+	recv := ifce.recv()
+	results := fieldList(field(&ast.ArrayType{Elt: selectorExpr(ast.NewIdent("tablemock"), "Call")}))
+
+	return funcDecl(recv, ifce.helperName("Calls"), fieldList(), results, body)
+}
+
+// record returns the statement that adds the current call to the fake's
+// recorder and keeps it as fakeCall. It must run before the call counter is
+// incremented so the recorded index matches the key in the method map.
+func (meth Method) record(ifce Interface, index ast.Expr) ast.Stmt {
+	recorder := selectorExpr(selectorExpr(ast.NewIdent("fake"), "opts"), "Recorder")
+	args := expression(ast.NewIdent("fake"), meth.assertName(ifce), index)
+	for _, arg := range meth.Args {
+		args = append(args, ast.NewIdent(arg.argName()))
+	}
+
+	return &ast.AssignStmt{
+		Lhs: expression(ast.NewIdent("fakeCall")),
+		Rhs: expression(call(selectorExpr(recorder, "Record"), args...)),
+		Tok: token.DEFINE,
+	}
+}
+
+// unexpected returns the statement that reports a call with no configured
+// return or stub, as told by the unprogrammed condition, to a strict fake. It
+// has to run after the method's lock is released because a strict fake may
+// end the test with t.Fatalf.
+func unexpected(unprogrammed ast.Expr) ast.Stmt {
+	opts := selectorExpr(ast.NewIdent("fake"), "opts")
+
+	return &ast.IfStmt{
+		Cond: unprogrammed,
+		Body: blockStmt(exprStmt(call(selectorExpr(opts, "Unexpected"), ast.NewIdent("fakeCall")))),
+	}
+}
@@ -0,0 +1,235 @@
+package mock_test
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/vitreuz/table-mocks/mock"
+)
+
+const (
+	repoPath   = "github.com/vitreuz/table-mocks"
+	corpusPath = "corpus.test"
+)
+
+// dirImporter type checks packages straight from source so that the fakes can
+// be checked without a network or a build cache. Import paths under one of the
+// roots are read from disk, anything else is left to the source importer.
+type dirImporter struct {
+	fset  *token.FileSet
+	roots map[string]string
+	pkgs  map[string]*types.Package
+	std   types.Importer
+}
+
+func newDirImporter(fset *token.FileSet, roots map[string]string) *dirImporter {
+	return &dirImporter{
+		fset:  fset,
+		roots: roots,
+		pkgs:  make(map[string]*types.Package),
+		std:   importer.ForCompiler(fset, "source", nil),
+	}
+}
+
+func (imp *dirImporter) Import(path string) (*types.Package, error) {
+	if pkg, ok := imp.pkgs[path]; ok {
+		return pkg, nil
+	}
+
+	for root, dir := range imp.roots {
+		if path != root && !strings.HasPrefix(path, root+"/") {
+			continue
+		}
+
+		pkg, err := imp.check(path, filepath.Join(dir, strings.TrimPrefix(path, root)))
+		if err != nil {
+			return nil, err
+		}
+		imp.pkgs[path] = pkg
+		return pkg, nil
+	}
+
+	return imp.std.Import(path)
+}
+
+func (imp *dirImporter) check(path, dir string) (*types.Package, error) {
+	noTests := func(f os.FileInfo) bool { return !strings.HasSuffix(f.Name(), "_test.go") }
+	pkgs, err := parser.ParseDir(imp.fset, dir, noTests, parser.AllErrors)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("expected one package in %s but got %d", dir, len(pkgs))
+	}
+
+	var files []*ast.File
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			files = append(files, file)
+		}
+	}
+
+	var errs []string
+	conf := types.Config{
+		Importer: imp,
+		Error:    func(err error) { errs = append(errs, err.Error()) },
+	}
+	pkg, _ := conf.Check(path, imp.fset, files, nil)
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("type checking %s:\n\t%s", path, strings.Join(errs, "\n\t"))
+	}
+
+	return pkg, nil
+}
+
+// tempModule copies the go files of src into the root of a new module and
+// returns its directory.
+func tempModule(t *testing.T, module, src string) string {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "module_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "go.mod"), []byte("module "+module+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	names, err := filepath.Glob(filepath.Join(src, "*.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range names {
+		data, err := ioutil.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, filepath.Base(name)), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+// corpus returns the source packages every generated fake is checked
+// against: the interfaces design and each generator test case.
+func corpus(t *testing.T) map[string]string {
+	inputs, err := filepath.Glob("testdata/*/input.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dirs := map[string]string{"interfaces_design": "../interfaces_design"}
+	for _, input := range inputs {
+		dirs[filepath.Base(filepath.Dir(input))] = filepath.Dir(input)
+	}
+	return dirs
+}
+
+func TestGenerateCompiles(t *testing.T) {
+	repo, err := filepath.Abs("..")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, dir := range corpus(t) {
+		dir := dir
+		t.Run(name, func(t *testing.T) {
+			modPath := corpusPath + "/" + name
+			module := tempModule(t, modPath, dir)
+
+			mock, err := ReadPkg(module, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(mock.Interfaces) == 0 {
+				t.Fatalf("expected to read interfaces from %s", dir)
+			}
+
+			fakes := filepath.Join(module, "fake")
+			if err := os.MkdirAll(fakes, 0755); err != nil {
+				t.Fatal(err)
+			}
+			for _, ifce := range mock.Interfaces {
+				ifce := ifce
+				if ifce.PkgPath != modPath {
+					t.Errorf("expected %s to be read from %s but got %q", ifce.Name, modPath, ifce.PkgPath)
+				}
+
+				f, err := os.Create(fakeFile(module, ifce))
+				if err != nil {
+					t.Fatal(err)
+				}
+				pkg := "fake"
+				if ifce.Local() {
+					pkg = ifce.Package
+				}
+				err = GenerateFile(&ifce, pkg, f)
+				f.Close()
+				if err != nil {
+					t.Fatalf("generating %s: %s", ifce.Name, err)
+				}
+			}
+
+			fset := token.NewFileSet()
+			imp := newDirImporter(fset, map[string]string{
+				repoPath: repo,
+				modPath:  module,
+			})
+			if _, err := imp.Import(modPath); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := imp.Import(modPath + "/fake"); err != nil && !allLocal(mock.Interfaces) {
+				t.Fatal(err)
+			}
+
+			for _, ifce := range mock.Interfaces {
+				data, err := ioutil.ReadFile(fakeFile(module, ifce))
+				if err != nil {
+					t.Fatal(err)
+				}
+				typ := mock.Package + "." + ifce.Name
+				if ifce.Local() {
+					typ = ifce.Name
+				}
+				conformance := "var _ " + typ
+				if ifce.Func {
+					conformance = "Func() " + typ
+				}
+				if !strings.Contains(string(data), conformance) {
+					t.Errorf("expected the fake for %s to assert %q", ifce.Name, conformance)
+				}
+			}
+		})
+	}
+}
+
+// fakeFile is where the fake for ifce is written in module: the fake package,
+// or the module's own package for a fake that has to be generated into it.
+func fakeFile(module string, ifce Interface) string {
+	if ifce.Local() {
+		return filepath.Join(module, "zz_fake_"+strings.ToLower(ifce.Name)+".go")
+	}
+	return filepath.Join(module, "fake", strings.ToLower(ifce.Name)+".go")
+}
+
+func allLocal(ifces []Interface) bool {
+	for _, ifce := range ifces {
+		if !ifce.Local() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,62 @@
+package mock
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// samePackage reports whether a fake generated into pkg lives in the package
+// the interface was read from, in which case the interface needs no qualifier.
+func (ifce Interface) samePackage(pkg string) bool {
+	return ifce.Package != "" && ifce.Package == pkg
+}
+
+// generateConformance returns the `var _ design.Runner = (*Runner)(nil)`
+// declaration that makes the compiler check the fake still implements the
+// interface it was generated from. It returns nil when the source package is
+// unknown, and for a named func type, whose Func method does the check. A
+// generic interface can only be checked once instantiated, so its declaration
+// is wrapped in a generic func over the same type parameters.
+func (ifce Interface) generateConformance(pkg string) ast.Decl {
+	typ := ifce.sourceType(pkg)
+	if typ == nil || ifce.Func {
+		return nil
+	}
+
+	return ifce.implements(typ, ifce.fakeType())
+}
+
+// sourceType refers to the interface the fake was generated from, as seen
+// from pkg. It returns nil when the source package is unknown.
+func (ifce Interface) sourceType(pkg string) ast.Expr {
+	switch {
+	case ifce.samePackage(pkg):
+		return ast.NewIdent(ifce.Name)
+	case ifce.PkgPath != "":
+		return selectorExpr(ast.NewIdent(ifce.Package), ifce.Name)
+	}
+	return nil
+}
+
+// implements returns the `var _ typ = (impl)(nil)` declaration, with typ
+// instantiated with the type parameters of ifce.
+func (ifce Interface) implements(typ ast.Expr, impl ast.Expr) ast.Decl {
+	conformance := &ast.GenDecl{
+		Tok: token.VAR,
+		Specs: []ast.Spec{
+			&ast.ValueSpec{
+				Names:  []*ast.Ident{ast.NewIdent("_")},
+				Type:   instantiate(typ, ifce.TypeParams),
+				Values: expression(call(&ast.ParenExpr{X: impl}, ast.NewIdent("nil"))),
+			},
+		},
+	}
+	if len(ifce.TypeParams) == 0 {
+		return conformance
+	}
+
+	decl := funcDecl(nil, "_", fieldList(), nil, blockStmt(&ast.DeclStmt{Decl: conformance}))
+	decl.Type.TypeParams = ifce.typeParams()
+
+	return decl
+}
@@ -0,0 +1,108 @@
+package mock
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// isContext reports whether typ is context.Context.
+func isContext(typ ast.Expr) bool {
+	sel, ok := typ.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && pkg.Name == "context" && sel.Sel.Name == "Context"
+}
+
+// takesContext reports whether the method's first arg is a context.Context.
+func (meth Method) takesContext() bool {
+	return len(meth.Args) > 0 && isContext(meth.Args[0].Type)
+}
+
+// contextArg returns the method's leading context.Context arg, or nil when it
+// doesn't take one.
+func (meth Method) contextArg() ast.Expr {
+	if meth.takesContext() {
+		return ast.NewIdent(meth.Args[0].argName())
+	}
+	return ast.NewIdent("nil")
+}
+
+// errorResult returns the method's trailing error result, if it has one.
+func (meth Method) errorResult() (Value, bool) {
+	if len(meth.Rets) == 0 {
+		return Value{}, false
+	}
+	ret := meth.Rets[len(meth.Rets)-1]
+	ident, ok := ret.Type.(*ast.Ident)
+	return ret, ok && ident.Name == "error"
+}
+
+// deadlineName names the per call field noting whether the call's context
+// carried a deadline, e.g. CtxHasDeadline.
+func (meth Method) deadlineName() string {
+	return meth.Args[0].fieldName() + "HasDeadline"
+}
+
+func (meth Method) deadlineField() *ast.Field {
+	return field(ast.NewIdent("bool"), meth.deadlineName())
+}
+
+// noteDeadline returns the statement keeping whether the call's context
+// carried a deadline.
+func (meth Method) noteDeadline(fakeMethod ast.Expr) ast.Stmt {
+	ctx := meth.contextArg()
+	return assign(selectorExpr(fakeMethod, meth.deadlineName()), call(selectorExpr(ast.NewIdent("tablemock"), "HasDeadline"), ctx))
+}
+
+// honorContext returns the statements passing the call's gate and sleeping
+// for its delay, in place of pass and sleep, for a method taking a context
+// and returning an error. A call whose context is done while it's blocked or
+// sleeping, or once it's through when the fake was built with HonorContext,
+// ends with the context's error. The other results are left zero, and the
+// call's record keeps what it returned.
+func (meth Method) honorContext() []ast.Stmt {
+	fake := ast.NewIdent("fake")
+	fakeMethod := ast.NewIdent("fakeMethod")
+	fakeMethodMutex := selectorExpr(fake, meth.mutexName())
+	ctxErr := ast.NewIdent("fakeCtxErr")
+
+	results := expression()
+	values := expression()
+	returns := &ast.ReturnStmt{}
+	for i, ret := range meth.Rets {
+		results = append(results, selectorExpr(fakeMethod, ret.fieldName()))
+		if i == len(meth.Rets)-1 {
+			values = append(values, ctxErr)
+		} else {
+			values = append(values, ast.NewIdent(ret.argName()))
+		}
+		returns.Results = append(returns.Results, selectorExpr(fakeMethod, ret.fieldName()))
+	}
+
+	stmts := []ast.Stmt{&ast.AssignStmt{Lhs: expression(ctxErr), Tok: token.DEFINE, Rhs: expression(meth.passCall())}}
+	for _, next := range []ast.Expr{
+		meth.sleepCall(fakeMethod),
+		call(selectorExpr(selectorExpr(fake, "opts"), "ContextErr"), meth.contextArg()),
+	} {
+		stmts = append(stmts, &ast.IfStmt{
+			Cond: &ast.BinaryExpr{X: ctxErr, Op: token.EQL, Y: ast.NewIdent("nil")},
+			Body: blockStmt(assign(ctxErr, next)),
+		})
+	}
+
+	return append(stmts, &ast.IfStmt{
+		Cond: &ast.BinaryExpr{X: ctxErr, Op: token.NEQ, Y: ast.NewIdent("nil")},
+		Body: blockStmt(
+			&ast.AssignStmt{Lhs: results, Tok: token.ASSIGN, Rhs: values},
+			exprStmt(call(selectorExpr(fakeMethodMutex, "Lock"))),
+			assign(&ast.IndexExpr{
+				X:     selectorExpr(fake, meth.recordName()),
+				Index: selectorExpr(ast.NewIdent("fakeCall"), "Index"),
+			}, fakeMethod),
+			exprStmt(call(selectorExpr(fakeMethodMutex, "Unlock"))),
+			returns,
+		),
+	})
+}
@@ -0,0 +1,78 @@
+package mock
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// delayField is the per call field holding how long a call sleeps for.
+const delayField = "DelayValue"
+
+func (method Method) delayName() string {
+	return toMethodName(method.Name, "Delay")
+}
+
+func (method Method) delaysName() string {
+	return toMethodName(method.Name, "Delays")
+}
+
+func durationType() ast.Expr {
+	return selectorExpr(ast.NewIdent("time"), "Duration")
+}
+
+// callDelay returns the statement giving a call the delay DelayOnCall set
+// for it, or else the delay set by Delay unless its returns carry their own.
+// It has to run while holding the method's lock.
+func (meth Method) callDelay(fakeMethod ast.Expr) ast.Stmt {
+	fake := ast.NewIdent("fake")
+	delay := selectorExpr(fakeMethod, delayField)
+	onCall := ast.NewIdent("delay")
+
+	return &ast.IfStmt{
+		Init: &ast.AssignStmt{
+			Lhs: expression(onCall, ast.NewIdent("ok")),
+			Tok: token.DEFINE,
+			Rhs: expression(&ast.IndexExpr{X: selectorExpr(fake, meth.delaysName()), Index: selectorExpr(fake, meth.callsName())}),
+		},
+		Cond: ast.NewIdent("ok"),
+		Body: blockStmt(assign(delay, onCall)),
+		Else: &ast.IfStmt{
+			Cond: &ast.BinaryExpr{X: delay, Op: token.EQL, Y: &ast.BasicLit{Kind: token.INT, Value: "0"}},
+			Body: blockStmt(assign(delay, selectorExpr(fake, meth.delayName()))),
+		},
+	}
+}
+
+// sleep returns the statement sleeping for the call's delay on the fake's
+// clock, cut short once the call's context is done.
+func (meth Method) sleep(fakeMethod ast.Expr) ast.Stmt {
+	return exprStmt(meth.sleepCall(fakeMethod))
+}
+
+func (meth Method) sleepCall(fakeMethod ast.Expr) ast.Expr {
+	opts := selectorExpr(ast.NewIdent("fake"), "opts")
+	return call(selectorExpr(opts, "Sleep"), meth.contextArg(), selectorExpr(fakeMethod, delayField))
+}
+
+// generateDelays returns RunDelay and RunDelayOnCall.
+func (meth Method) generateDelays(ifce Interface) []ast.Decl {
+	fake := ast.NewIdent("fake")
+	fakeMethodMutex := selectorExpr(fake, meth.mutexName())
+
+	body := blockStmt(
+		exprStmt(call(selectorExpr(fakeMethodMutex, "Lock"))),
+		assign(selectorExpr(fake, meth.delayName()), ast.NewIdent("delay")),
+		exprStmt(call(selectorExpr(fakeMethodMutex, "Unlock"))),
+		&ast.ReturnStmt{Results: expression(fake)},
+	)
+
+	recv := ifce.recv()
+	params := fieldList(field(durationType(), "delay"))
+	results := fieldList(field(ifce.fakeType()))
+
+	return []ast.Decl{
+		funcDecl(recv, strings.Title(meth.Name)+"Delay", params, results, body),
+		meth.generateOnCall(ifce, "DelayOnCall", field(durationType(), "delay"), meth.delaysName()),
+	}
+}
@@ -0,0 +1,292 @@
+package mock
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/Sirupsen/logrus.v0"
+)
+
+// Extracted is an interface extracted from the exported method set of a
+// struct type, as it's declared in the struct's own package. Interface holds
+// its name, package, type parameters and imports, Methods its method fields
+// with the arg and result names of the source.
+type Extracted struct {
+	Struct    string
+	Interface Interface
+	Methods   []*ast.Field
+}
+
+// ExtractedName names the interface extracted from a struct. An unexported
+// struct like s3Client gives S3Client, an exported one like Client gives
+// ClientInterface so as not to clash with the struct itself.
+func ExtractedName(structName string) string {
+	if name := strings.Title(structName); name != structName {
+		return name
+	}
+	return structName + "Interface"
+}
+
+// ReadStruct reads the exported methods of the struct type name in dir, both
+// the ones declared on it and the ones promoted from the structs of the
+// package it embeds. It returns the Mock to generate a fake of the extracted interface
+// from, along with the interface's declaration.
+func ReadStruct(dir, name string, opts ...ReadOption) (*Mock, *Extracted, error) {
+	gopath := gopathDir()
+	config := newReadConfig(opts...)
+
+	logrus.WithFields(logrus.Fields{
+		"gopath": gopath,
+		"dir":    dir,
+		"struct": name,
+	}).Println("reading struct")
+
+	pkg, pkgPath, files, err := parseDir(dir, gopath, config.build)
+	if err != nil {
+		return nil, nil, err
+	}
+	if pkg == nil {
+		return nil, nil, fmt.Errorf("no package in %s", dir)
+	}
+	spec, ok := structSpec(pkg.Scope.Objects[name])
+	if !ok {
+		return nil, nil, fmt.Errorf("%s is not a struct type in %s", name, dir)
+	}
+
+	// The fake refers to the types of the package by their qualified names,
+	// the declaration lives in the package and must not.
+	pp := NewPackageParser(ast.NewIdent(pkg.Name))
+	pp.scope = pkg.Scope.Objects
+	pp.imports = make(map[string]struct{})
+	local := NewPackageParser(ast.NewIdent(pkg.Name))
+	local.imports = make(map[string]struct{})
+
+	ifce := Interface{Name: ExtractedName(name), Package: pkg.Name, PkgPath: pkgPath}
+	decl := Interface{Name: ifce.Name, Package: pkg.Name, PkgPath: pkgPath}
+	ifce.TypeParams = pp.parseTypeParams(spec.TypeParams)
+	decl.TypeParams = local.parseTypeParams(spec.TypeParams)
+
+	importCache := make(map[string]string)
+	decls := make(map[string][]*ast.FuncDecl)
+	for _, fname := range files {
+		node := pkg.Files[fname]
+		imports, err := fileImports(node, gopath)
+		if err != nil {
+			return nil, nil, err
+		}
+		for imp, path := range imports {
+			importCache[imp] = path
+		}
+
+		for _, d := range node.Decls {
+			if funcTok, ok := d.(*ast.FuncDecl); ok {
+				recv := receiverName(funcTok.Recv)
+				decls[recv] = append(decls[recv], funcTok)
+			}
+		}
+	}
+
+	funcToks, err := methodSet(pkg.Scope.Objects, spec, decls)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot extract %s: %v", name, err)
+	}
+	var methods []*ast.Field
+	for _, funcTok := range funcToks {
+		method := Method{Name: funcTok.Name.Name}
+		method.Args, method.Rets = pp.parseFuncToken(funcTok.Type)
+		ifce.Methods = append(ifce.Methods, method)
+
+		methods = append(methods, &ast.Field{
+			Names: []*ast.Ident{ast.NewIdent(funcTok.Name.Name)},
+			Type: &ast.FuncType{
+				Params:  local.parseFieldList(funcTok.Type.Params),
+				Results: local.parseFieldList(funcTok.Type.Results),
+			},
+		})
+	}
+	ifce.Imports = pp.resolveImports(importCache, pkgPath)
+	ifce.Unexported = pp.unexportedTypes()
+	decl.Imports = local.resolveImports(importCache, "")
+
+	mock := &Mock{Package: pkg.Name, Interfaces: []Interface{ifce}}
+	return mock, &Extracted{Struct: name, Interface: decl, Methods: methods}, nil
+}
+
+// methodSet returns the exported methods of the struct spec, declared on it or
+// promoted from the types of the package it embeds, as decls holds them by
+// receiver. As in Go, a method or field hides the ones deeper in the embedded
+// fields, and a name found twice at the same depth is promoted from neither.
+// The methods of types from other packages, embedded interfaces and generic
+// types can't be read, so embedding one is an error.
+func methodSet(scope map[string]*ast.Object, spec *ast.TypeSpec, decls map[string][]*ast.FuncDecl) ([]*ast.FuncDecl, error) {
+	var methods []*ast.FuncDecl
+	hidden := make(map[string]bool)
+	visited := map[*ast.TypeSpec]bool{spec: true}
+	for level := []*ast.TypeSpec{spec}; len(level) > 0; {
+		var next []*ast.TypeSpec
+		var names []string
+		found := make(map[string]*ast.FuncDecl)
+		count := make(map[string]int)
+		for _, spec := range level {
+			for _, funcTok := range decls[spec.Name.Name] {
+				name := funcTok.Name.Name
+				if hidden[name] {
+					continue
+				}
+				names = append(names, name)
+				found[name] = funcTok
+				count[name]++
+			}
+
+			structTok, ok := spec.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+			for _, fieldTok := range structTok.Fields.List {
+				for _, ident := range fieldTok.Names {
+					count[ident.Name]++
+				}
+				if len(fieldTok.Names) > 0 {
+					continue
+				}
+
+				typ := fieldTok.Type
+				if star, ok := typ.(*ast.StarExpr); ok {
+					typ = star.X
+				}
+				embedded, ok := embeddedType(scope, typ)
+				if !ok {
+					return nil, fmt.Errorf("%s: methods promoted from %s can't be read", fset.Position(fieldTok.Pos()), types.ExprString(fieldTok.Type))
+				}
+				count[embedded.Name.Name]++
+				if !visited[embedded] {
+					visited[embedded] = true
+					next = append(next, embedded)
+				}
+			}
+		}
+
+		for _, name := range names {
+			if count[name] == 1 && ast.IsExported(name) {
+				methods = append(methods, found[name])
+			}
+		}
+		for name := range count {
+			hidden[name] = true
+		}
+		level = next
+	}
+
+	return methods, nil
+}
+
+// embeddedType returns the declaration of the type an embedded field names,
+// if its methods can be read: it's declared in the package, isn't generic and
+// isn't an interface.
+func embeddedType(scope map[string]*ast.Object, typ ast.Expr) (*ast.TypeSpec, bool) {
+	ident, ok := typ.(*ast.Ident)
+	if !ok || scope[ident.Name] == nil {
+		return nil, false
+	}
+	spec, ok := scope[ident.Name].Decl.(*ast.TypeSpec)
+	if !ok || spec.TypeParams != nil {
+		return nil, false
+	}
+	_, ok = spec.Type.(*ast.InterfaceType)
+	return spec, !ok
+}
+
+func structSpec(obj *ast.Object) (*ast.TypeSpec, bool) {
+	if obj == nil {
+		return nil, false
+	}
+	spec, ok := obj.Decl.(*ast.TypeSpec)
+	if !ok {
+		return nil, false
+	}
+	_, ok = spec.Type.(*ast.StructType)
+	return spec, ok
+}
+
+// receiverName returns the name of the type a method is declared on, or an
+// empty string for funcs.
+func receiverName(recv *ast.FieldList) string {
+	if recv == nil || len(recv.List) != 1 {
+		return ""
+	}
+
+	typ := recv.List[0].Type
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	switch index := typ.(type) {
+	case *ast.IndexExpr:
+		typ = index.X
+	case *ast.IndexListExpr:
+		typ = index.X
+	}
+	if ident, ok := typ.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// GenerateExtracted writes the declaration of an extracted interface, along
+// with the assertion that the struct implements it.
+func GenerateExtracted(ext *Extracted, file *os.File) error {
+	src, err := printFile(ext.ToFile())
+	if err != nil {
+		return err
+	}
+	_, err = file.WriteString(src)
+	return err
+}
+
+// extractedHeader marks a file declaring an extracted interface. It differs
+// from the header of a fake, as the interface is one of the package's own and
+// has to be read along with the rest of it.
+const extractedHeader = "// extracted by table-mocks; DO NOT EDIT"
+
+// ToFile builds the file declaring the extracted interface in the package of
+// the struct.
+func (ext Extracted) ToFile() *ast.File {
+	ifce := ext.Interface
+	node := &ast.File{
+		Name:     ast.NewIdent(ifce.Package),
+		Comments: []*ast.CommentGroup{{List: []*ast.Comment{{Text: extractedHeader}}}},
+	}
+
+	if len(ifce.Imports) > 0 {
+		imports := append([]string{}, ifce.Imports...)
+		sort.Strings(imports)
+
+		imprt := &ast.GenDecl{Tok: token.IMPORT}
+		for _, imp := range imports {
+			imprt.Specs = append(imprt.Specs, &ast.ImportSpec{
+				Path: &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(imp)},
+			})
+		}
+		node.Decls = append(node.Decls, imprt)
+	}
+
+	typeDecl := &ast.GenDecl{
+		Tok: token.TYPE,
+		Specs: []ast.Spec{
+			&ast.TypeSpec{
+				Name:       ast.NewIdent(ifce.Name),
+				TypeParams: ifce.typeParams(),
+				Type:       &ast.InterfaceType{Methods: fieldList(ext.Methods...)},
+			},
+		},
+	}
+	impl := &ast.StarExpr{X: ifce.typeRef(ext.Struct)}
+	node.Decls = append(node.Decls, typeDecl, ifce.implements(ast.NewIdent(ifce.Name), impl))
+
+	return node
+}
@@ -0,0 +1,199 @@
+package mock_test
+
+import (
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/vitreuz/table-mocks/mock"
+)
+
+func TestReadStruct(t *testing.T) {
+	check := func(fns ...checkReader) []checkReader { return fns }
+
+	tests := [...]struct {
+		name    string
+		strct   string
+		ifce    string
+		methods []string
+		checks  []checkReader
+	}{
+		{
+			"Unexported struct",
+			"s3Client",
+			"S3Client",
+			[]string{"Get", "Put", "List"},
+			check(expectReader(strings.NewReader(`
+// extracted by table-mocks; DO NOT EDIT
+
+package store
+
+import (
+	"context"
+	"io"
+)
+
+type S3Client interface {
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Put(ctx context.Context, key string, body io.Reader) error
+	List(prefix string, limit ...int) ([]Object, error)
+}
+
+var _ S3Client = (*s3Client)(nil)
+`,
+			))),
+		}, {
+			"Generic struct",
+			"Cache",
+			"CacheInterface",
+			[]string{"Get", "Set"},
+			check(expectReader(strings.NewReader(`
+// extracted by table-mocks; DO NOT EDIT
+
+package store
+
+type CacheInterface[K comparable, V any] interface {
+	Get(key K) (V, bool)
+	Set(key K, value V)
+}
+
+func _[K comparable, V any]() {
+	var _ CacheInterface[K, V] = (*Cache[K, V])(nil)
+}
+`,
+			))),
+		}, {
+			"Embedded struct",
+			"gcsClient",
+			"GcsClient",
+			[]string{"Get", "Bucket", "Close"},
+			check(expectReader(strings.NewReader(`
+// extracted by table-mocks; DO NOT EDIT
+
+package store
+
+type GcsClient interface {
+	Get(key string) ([]byte, error)
+	Bucket() string
+	Close() error
+}
+
+var _ GcsClient = (*gcsClient)(nil)
+`,
+			))),
+		},
+	}
+
+	repo, err := filepath.Abs("..")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			modPath := corpusPath + "/store"
+			module := tempModule(t, modPath, "testdata/extract")
+
+			mock, ext, err := ReadStruct(module, tt.strct)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(mock.Interfaces) != 1 {
+				t.Fatalf("expected one interface but got %d", len(mock.Interfaces))
+			}
+			ifce := mock.Interfaces[0]
+			if ifce.Name != tt.ifce {
+				t.Errorf("expected interface %s but got %s", tt.ifce, ifce.Name)
+			}
+			var methods []string
+			for _, method := range ifce.Methods {
+				methods = append(methods, method.Name)
+			}
+			if strings.Join(methods, ",") != strings.Join(tt.methods, ",") {
+				t.Errorf("expected methods %v but got %v", tt.methods, methods)
+			}
+
+			declName := filepath.Join(module, "extracted.go")
+			decl, err := os.Create(declName)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = GenerateExtracted(ext, decl)
+			decl.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+			output, err := ioutil.ReadFile(declName)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, check := range tt.checks {
+				for _, checkErr := range check(strings.NewReader(string(output))) {
+					if checkErr != nil {
+						t.Error(checkErr)
+					}
+				}
+			}
+
+			read, err := ReadPkg(module, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(read.Interfaces) != 1 || read.Interfaces[0].Name != tt.ifce {
+				t.Errorf("expected ReadPkg to read the extracted %s but got %d interfaces", tt.ifce, len(read.Interfaces))
+			}
+
+			if err := os.MkdirAll(filepath.Join(module, "fake"), 0755); err != nil {
+				t.Fatal(err)
+			}
+			fake, err := os.Create(filepath.Join(module, "fake", "fake.go"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = GenerateFile(&ifce, "fake", fake)
+			fake.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			imp := newDirImporter(token.NewFileSet(), map[string]string{
+				repoPath: repo,
+				modPath:  module,
+			})
+			if _, err := imp.Import(modPath + "/fake"); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestReadStructErrors(t *testing.T) {
+	empty, err := ioutil.TempDir("", "read_struct_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(empty)
+
+	tests := [...]struct {
+		name   string
+		dir    string
+		strct  string
+		expect string
+	}{
+		{"Not a struct", "testdata/extract", "Missing", "Missing is not a struct type in testdata/extract"},
+		{"No package", empty, "Client", "no package in " + empty},
+		{"Embedded type of another package", "testdata/extract", "lockedClient", "cannot extract lockedClient: testdata/extract/client.go:61:2: methods promoted from sync.Mutex can't be read"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := ReadStruct(tt.dir, tt.strct)
+			if err == nil || err.Error() != tt.expect {
+				t.Errorf("expected error %q but got %v", tt.expect, err)
+			}
+		})
+	}
+}
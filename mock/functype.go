@@ -0,0 +1,35 @@
+package mock
+
+import (
+	"go/ast"
+)
+
+// funcMethod is the name of the method a named func type is faked with.
+const funcMethod = "Call"
+
+// funcType returns the type Func returns: the named func type as seen from
+// pkg or, when its package is unknown, the func type it's declared with.
+func (ifce Interface) funcType(pkg string) ast.Expr {
+	if typ := ifce.sourceType(pkg); typ != nil {
+		return instantiate(typ, ifce.TypeParams)
+	}
+
+	meth := ifce.Methods[0]
+	params := fieldList()
+	for _, arg := range meth.Args {
+		params.List = append(params.List, arg.variable())
+	}
+	results := fieldList()
+	for _, ret := range meth.Rets {
+		results.List = append(results.List, ret.variable())
+	}
+	return &ast.FuncType{Params: params, Results: results}
+}
+
+// generateFunc returns Func, which hands out the fake of a named func type as
+// a value of that type. Returning fake.Call also has the compiler check the
+// fake still matches the func type.
+func (ifce Interface) generateFunc(pkg string) *ast.FuncDecl {
+	body := blockStmt(&ast.ReturnStmt{Results: expression(selectorExpr(ast.NewIdent("fake"), funcMethod))})
+	return funcDecl(ifce.recv(), "Func", fieldList(), fieldList(field(ifce.funcType(pkg))), body)
+}
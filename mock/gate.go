@@ -0,0 +1,52 @@
+package mock
+
+import (
+	"go/ast"
+	"strings"
+)
+
+func (method Method) gateName() string {
+	return toMethodName(method.Name, "Gate")
+}
+
+// countCalls tells the method's gate the call count changed. Like every
+// change to the count, it has to happen while holding the method's lock.
+func (meth Method) countCalls() ast.Stmt {
+	fake := ast.NewIdent("fake")
+	return exprStmt(call(selectorExpr(selectorExpr(fake, meth.gateName()), "Count"), selectorExpr(fake, meth.callsName())))
+}
+
+// pass returns the statement holding a call while its method is blocked,
+// until the test releases it or the call's context is done.
+func (meth Method) pass() ast.Stmt {
+	return exprStmt(meth.passCall())
+}
+
+func (meth Method) passCall() ast.Expr {
+	gate := selectorExpr(ast.NewIdent("fake"), meth.gateName())
+	return call(selectorExpr(gate, "Pass"), meth.contextArg())
+}
+
+// generateGate returns RunBlock, RunRelease and RunWaitForCalls.
+func (meth Method) generateGate(ifce Interface) []ast.Decl {
+	gate := selectorExpr(ast.NewIdent("fake"), meth.gateName())
+	recv := ifce.recv()
+
+	block := funcDecl(recv, strings.Title(meth.Name)+"Block", fieldList(), fieldList(field(ifce.fakeType())), blockStmt(
+		exprStmt(call(selectorExpr(gate, "Block"))),
+		&ast.ReturnStmt{Results: expression(ast.NewIdent("fake"))},
+	))
+	release := funcDecl(recv, strings.Title(meth.Name)+"Release", fieldList(), fieldList(), blockStmt(
+		exprStmt(call(selectorExpr(gate, "Release"))),
+	))
+
+	params := fieldList(
+		field(selectorExpr(ast.NewIdent("context"), "Context"), "ctx"),
+		field(ast.NewIdent("int"), "n"),
+	)
+	wait := funcDecl(recv, strings.Title(meth.Name)+"WaitForCalls", params, fieldList(field(ast.NewIdent("error"))), blockStmt(
+		&ast.ReturnStmt{Results: expression(call(selectorExpr(gate, "WaitForCalls"), ast.NewIdent("ctx"), ast.NewIdent("n")))},
+	))
+
+	return []ast.Decl{block, release, wait}
+}
@@ -2,94 +2,76 @@ package mock
 
 import (
 	"bytes"
-	"fmt"
 	"go/ast"
 	"go/format"
 	"go/token"
-	"io"
 	"os"
+	"path"
+	"sort"
+	"strconv"
 	"strings"
 	"unicode"
 	"unicode/utf8"
 )
 
-func GenerateFile(ifce *Interface, pkg string, file *os.File) error {
-	buf := new(bytes.Buffer)
-
-	fmt.Fprintln(buf, "// generated by table-mocks; DO NOT EDIT")
-	buf.WriteString("\n")
-	buf.WriteString(GenerateHeader(ifce))
-	buf.WriteString("\n")
-	buf.WriteString(GenerateInterfaceStruct(ifce))
-	buf.WriteString("\n")
-	for _, method := range ifce.Methods {
-		buf.WriteString(GenerateMethodStruct(ifce.Name, method))
-		buf.WriteString("\n")
-	}
-	buf.WriteString(GenerateInterfaceConstructor(ifce))
-	buf.WriteString("\n")
-	for _, method := range ifce.Methods {
-		buf.WriteString(GenerateMethodFunc(ifce.Name, method))
-		buf.WriteString("\n")
-		buf.WriteString(GenerateMethodReturns(ifce.Name, method))
-		buf.WriteString("\n")
-		buf.WriteString(GenerateMethodGetArgs(ifce.Name, method))
-		buf.WriteString("\n")
-		buf.WriteString(GenerateExtensions(ifce.Name, method))
-	}
+const header = "// generated by table-mocks; DO NOT EDIT"
 
-	_, err := io.Copy(file, buf)
-	return err
+// GenerateFile writes the fake for ifce as a file of package pkg, as rendered
+// by DefaultTemplate.
+func GenerateFile(ifce *Interface, pkg string, file *os.File) error {
+	return GenerateTemplate(defaultTemplate, ifce, pkg, file)
 }
 
-func GenerateHeader(ifce *Interface) string {
-	node := &ast.File{Name: ast.NewIdent("fake")}
-
-	ifce.addSyncImport()
-	node.Decls = ifce.toImports()
-
-	buf := new(bytes.Buffer)
-	format.Node(buf, token.NewFileSet(), node)
-
-	byt, err := format.Source(buf.Bytes())
-	if err != nil {
-		panic(err)
+// ToFile builds the complete file holding the fake for ifce in package pkg.
+func (ifce Interface) ToFile(pkg string) *ast.File {
+	ifce = ifce.inPackage(pkg).withLocalNames(pkg)
+	node := &ast.File{
+		Name:     ast.NewIdent(pkg),
+		Comments: []*ast.CommentGroup{{List: []*ast.Comment{{Text: header}}}},
 	}
-	return string(byt)
-}
 
-func generateFile(ifce *Interface, pkg string, file *os.File) error {
-	ifce.addSyncImport()
-
-	node := ifce.ToFile(pkg)
-	fset = token.NewFileSet()
-	return format.Node(file, fset, node)
-}
+	node.Decls = ifce.toImports(pkg)
+	if conformance := ifce.generateConformance(pkg); conformance != nil {
+		node.Decls = append(node.Decls, conformance)
+	}
+	node.Decls = append(node.Decls, ifce.GenerateStructs(pkg)...)
+	node.Decls = append(node.Decls, ifce.GenerateMethods(pkg)...)
 
-func (m *Interface) addSyncImport() {
-	m.Imports = append(m.Imports, "sync")
+	return node
 }
 
-func (ifce Interface) ToFile(pkg string) *ast.File {
-	node := &ast.File{Name: ast.NewIdent("fake")}
+// imports returns the sorted import paths the fake for ifce needs.
+func (ifce Interface) imports(pkg string) []string {
+	imports := append([]string{"io", "sync", runtimeImport}, ifce.Imports...)
+	if len(ifce.Methods) > 0 {
+		imports = append(imports, "context", "testing", "time", matchImport)
+	}
+	if ifce.PkgPath != "" && !ifce.samePackage(pkg) {
+		imports = append(imports, ifce.PkgPath)
+	}
 
-	node.Decls = ifce.toImports()
-	node.Decls = append(node.Decls, ifce.GenerateStructs()...)
-	node.Decls = append(node.Decls, ifce.GenerateMethods()...)
+	seen := make(map[string]bool)
+	paths := []string{}
+	for _, imp := range imports {
+		if !seen[imp] {
+			seen[imp] = true
+			paths = append(paths, imp)
+		}
+	}
+	sort.Strings(paths)
 
-	return node
+	return paths
 }
 
-func (m Interface) toImports() []ast.Decl {
+func (ifce Interface) toImports(pkg string) []ast.Decl {
 	node := &ast.GenDecl{
-		Lparen: 2,
-		Tok:    token.IMPORT,
-		Specs:  []ast.Spec{},
+		Tok:   token.IMPORT,
+		Specs: []ast.Spec{},
 	}
 
-	for i := range m.Imports {
+	for _, imp := range ifce.imports(pkg) {
 		imprtSpec := &ast.ImportSpec{
-			Path: &ast.BasicLit{Kind: token.STRING, Value: fmt.Sprintf("%q", m.Imports[i])},
+			Path: &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(imp)},
 		}
 		node.Specs = append(node.Specs, imprtSpec)
 	}
@@ -97,127 +79,216 @@ func (m Interface) toImports() []ast.Decl {
 	return []ast.Decl{node}
 }
 
-func (ifce Interface) GenerateStructs() []ast.Decl {
-	decls := []ast.Decl{ifce.generateInterfaceStruct()}
+func (ifce Interface) GenerateStructs(pkg string) []ast.Decl {
+	decls := []ast.Decl{ifce.generateInterfaceStruct(pkg)}
 	for _, method := range ifce.Methods {
-		decls = append(decls, method.generateMethodStruct(ifce.Name))
+		decls = append(decls, method.generateMethodStruct(ifce))
 	}
 
 	return decls
 }
 
-func (ifce Interface) GenerateMethods() []ast.Decl {
+func (ifce Interface) GenerateMethods(pkg string) []ast.Decl {
 	// generate Constructor
 	decls := []ast.Decl{ifce.generateConstructor()}
+	if ifce.spies() {
+		decls = append(decls, ifce.generateSpyConstructor(pkg))
+	}
+	// generate Calls
+	decls = append(decls, ifce.generateCalls())
+	// generate Func
+	if ifce.Func {
+		decls = append(decls, ifce.generateFunc(pkg))
+	}
+	// generate Reset and Snapshot
+	decls = append(decls, ifce.generateResets()...)
+	// generate Record and LoadReplay
+	decls = append(decls, ifce.generateReplay()...)
+	return append(decls, ifce.methodDecls()...)
+}
+
+// methodDecls returns the declarations generated for each of the methods of
+// ifce, leaving out the fake-wide ones.
+func (ifce Interface) methodDecls() []ast.Decl {
+	decls := []ast.Decl{}
 	for _, method := range ifce.Methods {
 		// generate interfaceMethod
-		ifceMethod := method.generateInterfaceMethod(ifce.Name)
+		ifceMethod := method.generateInterfaceMethod(ifce)
 		// generate Returns
-		returns := method.generateReturns(ifce.Name)
+		returns := method.generateReturns(ifce)
 		// generate GetArgs
-		getArgs := method.generateGetArgs(ifce.Name)
+		getArgs := method.generateGetArgs(ifce)
 		// generate callback
-		callbck := method.generateCallback(ifce.Name)
+		callbck := method.generateCallback(ifce)
 		// generate ForCall
-		forCall := method.generateForCall(ifce.Name)
+		forCall := method.generateForCall(ifce)
 		decls = append(decls, ifceMethod, returns, getArgs, callbck, forCall)
+		// generate When
+		decls = append(decls, method.generateWhenStruct(ifce), method.generateWhen(ifce), method.generateWhenReturns(ifce))
+		// generate Block, Release and WaitForCalls
+		decls = append(decls, method.generateGate(ifce)...)
+		// generate PanicsOnCall, FailsOnCall and FailsEvery
+		decls = append(decls, method.generateInjections(ifce)...)
+		// generate Delay and DelayOnCall
+		decls = append(decls, method.generateDelays(ifce)...)
+		// generate ReturnsSequence, SequenceEnd and ForCallRange
+		decls = append(decls, method.generateSequence(ifce)...)
+		// generate SetsArg
+		if method.setsArgs(ifce) {
+			decls = append(decls, method.generateSets(ifce)...)
+		}
+		// generate Asserts
+		decls = append(decls, method.generateAsserts(ifce)...)
 	}
 	return decls
 }
 
-func cleanReturn(s *strings.Builder) string {
-	return strings.Replace(s.String(), "\n\treturn", "\n\n\treturn", -1) + "\n"
+// members returns the names of the fields of the fake for ifce and of the
+// methods generated for each of the methods of the interface.
+func (ifce Interface) members() map[string]bool {
+	members := make(map[string]bool)
+	spec := ifce.generateInterfaceStruct("").(*ast.GenDecl).Specs[0].(*ast.TypeSpec)
+	for _, field := range spec.Type.(*ast.StructType).Fields.List {
+		for _, name := range field.Names {
+			members[name.Name] = true
+		}
+	}
+	for _, decl := range ifce.methodDecls() {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv != nil {
+			members[fn.Name.Name] = true
+		}
+	}
+
+	return members
+}
+
+// helperName returns the name of a fake-wide helper such as Reset, with Fake
+// appended for as long as it clashes with one of the members of the fake. A
+// fake of hash.Hash gets ResetFake next to the Reset it fakes.
+func (ifce Interface) helperName(name string) string {
+	members := ifce.members()
+	for members[name] {
+		name += "Fake"
+	}
+	return name
 }
 
-func GenerateInterfaceStruct(ifce *Interface) string {
-	node := ifce.generateInterfaceStruct()
+// printFile lays out and prints a file built by ToFile.
+func printFile(node *ast.File) (string, error) {
+	l := new(layout)
+	l.file(node)
 
-	buf := new(strings.Builder)
-	format.Node(buf, token.NewFileSet(), node)
+	buf := new(bytes.Buffer)
+	if err := format.Node(buf, l.fileSet(), node); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
 
-	// TODO: this isn't elegant. If possible clean this up later
-	s := strings.Replace(buf.String(), "int\n", "int\n\n", -1)
-	s = strings.Replace(s, "\n\n}", "\n}", -1)
-	b, err := format.Source([]byte(s))
+// formatFile is printFile for the files built by the generator itself, which
+// always print.
+func formatFile(node *ast.File) string {
+	src, err := printFile(node)
 	if err != nil {
 		panic(err)
 	}
-
-	return string(b) + "\n"
+	return src
 }
 
-func GenerateInterfaceConstructor(ifce *Interface) string {
-	node := ifce.generateConstructor()
-
-	buf := new(strings.Builder)
-	format.Node(buf, token.NewFileSet(), node)
+// formatDecls lays out and prints declarations on their own, as they would
+// appear in a file built by ToFile.
+func formatDecls(decls ...ast.Decl) string {
+	l := new(layout)
+	l.decls(decls)
 
-	return cleanReturn(buf)
+	buf := new(bytes.Buffer)
+	if err := format.Node(buf, l.fileSet(), decls); err != nil {
+		panic(err)
+	}
+	return buf.String() + "\n"
 }
 
-func GenerateMethodStruct(ifce string, method Method) string {
-	node := method.generateMethodStruct(ifce)
+func GenerateHeader(ifce *Interface, pkg string) string {
+	return formatFile(&ast.File{Name: ast.NewIdent(pkg), Decls: ifce.toImports(pkg)})
+}
 
-	buf := new(strings.Builder)
-	format.Node(buf, token.NewFileSet(), node)
+func GenerateConformance(ifce *Interface, pkg string) string {
+	node := ifce.inPackage(pkg).generateConformance(pkg)
+	if node == nil {
+		return ""
+	}
 
-	return buf.String() + "\n"
+	return formatDecls(node)
 }
 
-func GenerateMethodFunc(ifce string, method Method) string {
-	node := method.generateInterfaceMethod(ifce)
+func GenerateFunc(ifce *Interface, pkg string) string {
+	return formatDecls(ifce.generateFunc(pkg))
+}
 
-	buf := new(strings.Builder)
-	format.Node(buf, token.NewFileSet(), node)
+func GenerateInterfaceStruct(ifce *Interface) string {
+	return formatDecls(ifce.generateInterfaceStruct(""))
+}
 
-	return cleanReturn(buf)
+func GenerateInterfaceConstructor(ifce *Interface) string {
+	return formatDecls(ifce.generateConstructor())
 }
 
-func GenerateMethodReturns(ifce string, method Method) string {
-	node := method.generateReturns(ifce)
+func GenerateInterfaceCalls(ifce *Interface) string {
+	return formatDecls(ifce.generateCalls())
+}
 
-	buf := new(strings.Builder)
-	format.Node(buf, token.NewFileSet(), node)
+func GenerateInterfaceResets(ifce *Interface) string {
+	return formatDecls(ifce.generateResets()...)
+}
 
-	return cleanReturn(buf)
+func GenerateMethodStruct(ifce string, method Method) string {
+	return formatDecls(method.generateMethodStruct(Interface{Name: ifce}))
 }
 
-func GenerateMethodGetArgs(ifce string, method Method) string {
-	node := method.generateGetArgs(ifce)
+func GenerateMethodFunc(ifce string, method Method) string {
+	return formatDecls(method.generateInterfaceMethod(Interface{Name: ifce}))
+}
 
-	buf := new(strings.Builder)
-	format.Node(buf, token.NewFileSet(), node)
+func GenerateMethodReturns(ifce string, method Method) string {
+	return formatDecls(method.generateReturns(Interface{Name: ifce}))
+}
 
-	return cleanReturn(buf)
+func GenerateMethodGetArgs(ifce string, method Method) string {
+	return formatDecls(method.generateGetArgs(Interface{Name: ifce}))
 }
 
 func GenerateExtensions(ifce string, method Method) string {
-	var node []ast.Decl
-	node = append(node, method.generateCallback(ifce))
-	node = append(node, method.generateForCall(ifce))
+	return formatDecls(method.generateCallback(Interface{Name: ifce}), method.generateForCall(Interface{Name: ifce}))
+}
 
-	buf := new(strings.Builder)
-	format.Node(buf, token.NewFileSet(), node)
+func GenerateMethodWhen(ifce string, method Method) string {
+	i := Interface{Name: ifce}
+	return formatDecls(method.generateWhenStruct(i), method.generateWhen(i), method.generateWhenReturns(i))
+}
 
-	return cleanReturn(buf)
+func GenerateMethodAsserts(ifce string, method Method) string {
+	return formatDecls(method.generateAsserts(Interface{Name: ifce})...)
 }
 
-func (meth Method) generateInterfaceMethod(ifceName string) *ast.FuncDecl {
+func (meth Method) generateInterfaceMethod(ifce Interface) *ast.FuncDecl {
 	fake := ast.NewIdent("fake")
 	fakeMethod := ast.NewIdent("fakeMethod")
 	fakeMethodField := selectorExpr(fake, meth.fieldName())
+	fakeMethodRecord := selectorExpr(fake, meth.recordName())
 	fakeMethodMutex := selectorExpr(fake, meth.mutexName())
 	fakeMethodCalls := selectorExpr(fake, meth.callsName())
+	configured := ast.NewIdent("configured")
 
 	body := blockStmt(
 		&ast.ExprStmt{
 			X: call(selectorExpr(fakeMethodMutex, "Lock")),
 		},
 		&ast.AssignStmt{
-			Lhs: expression(fakeMethod),
+			Lhs: expression(fakeMethod, configured),
 			Rhs: expression(&ast.IndexExpr{X: fakeMethodField, Index: fakeMethodCalls}),
 			Tok: token.DEFINE,
 		},
+		meth.sequenceReturns(fakeMethod, configured),
 	)
 
 	params := fieldList()
@@ -225,21 +296,48 @@ func (meth Method) generateInterfaceMethod(ifceName string) *ast.FuncDecl {
 		params.List = append(params.List, arg.variable())
 		body.List = append(body.List, arg.assignToField(fakeMethod))
 	}
+	if meth.takesContext() {
+		body.List = append(body.List, meth.noteDeadline(fakeMethod))
+	}
 
+	body.List = append(body.List, meth.matchWhen(fakeMethod, configured))
+	if _, ok := meth.errorResult(); ok {
+		body.List = append(body.List, meth.injectFailures(fakeMethod)...)
+	}
+	body.List = append(body.List, meth.takePanic(fakeMethod), meth.callDelay(fakeMethod))
+	if meth.setsArgs(ifce) {
+		body.List = append(body.List, meth.takeSets())
+	}
 	body.List = append(body.List, []ast.Stmt{
 		&ast.AssignStmt{
-			Lhs: expression(&ast.IndexExpr{X: fakeMethodField, Index: fakeMethodCalls}),
+			Lhs: expression(&ast.IndexExpr{X: fakeMethodRecord, Index: fakeMethodCalls}),
 			Rhs: expression(fakeMethod),
 			Tok: token.ASSIGN,
 		},
+		meth.record(ifce, fakeMethodCalls),
 		&ast.IncDecStmt{
 			X:   fakeMethodCalls,
 			Tok: token.INC,
 		},
+		meth.countCalls(),
 		&ast.ExprStmt{
 			X: call(selectorExpr(fakeMethodMutex, "Unlock")),
 		},
 	}...)
+	if _, ok := meth.errorResult(); ok && meth.takesContext() {
+		body.List = append(body.List, meth.honorContext()...)
+	} else {
+		body.List = append(body.List, meth.pass(), meth.sleep(fakeMethod))
+	}
+	body.List = append(body.List, meth.injectPanic(fakeMethod))
+	if meth.setsArgs(ifce) {
+		body.List = append(body.List, meth.applySets())
+	}
+	if ifce.spies() {
+		body.List = append(body.List, meth.forward(ifce, configured))
+	} else {
+		body.List = append(body.List, unexpected(meth.unprogrammed(configured)))
+	}
 
 	results := fieldList()
 	returns := &ast.ReturnStmt{}
@@ -249,7 +347,7 @@ func (meth Method) generateInterfaceMethod(ifceName string) *ast.FuncDecl {
 	}
 
 	body.List = append(body.List, returns)
-	recv := field(starExpr(strings.Title(ifceName)), "fake")
+	recv := ifce.recv()
 	funcName := meth.Name
 
 	return funcDecl(recv, funcName, params, results, body)
@@ -262,30 +360,48 @@ func (ifce Interface) generateConstructor() *ast.FuncDecl {
 		&ast.AssignStmt{
 			Lhs: expression(fake),
 			Tok: token.DEFINE,
-			Rhs: expression(compositeLit(strings.Title(ifce.Name), true)),
+			Rhs: expression(compositeLit(ifce.typeRef(ifce.fakeName()), true)),
 		},
 	)
 	for _, method := range ifce.Methods {
 		field := method.fieldName()
-		methodStruct := method.structName(ifce.Name)
 
 		asgn := &ast.AssignStmt{
 			Lhs: expression(selectorExpr(fake, field)),
 			Tok: token.ASSIGN,
-			Rhs: expression(call(ast.NewIdent("make"), simpleMap("int", methodStruct))),
+			Rhs: expression(call(ast.NewIdent("make"), ifce.methodMap(method))),
+		}
+		record := &ast.AssignStmt{
+			Lhs: expression(selectorExpr(fake, method.recordName())),
+			Tok: token.ASSIGN,
+			Rhs: expression(call(ast.NewIdent("make"), ifce.methodMap(method))),
 		}
-		body.List = append(body.List, asgn)
+		body.List = append(body.List, asgn, record)
 	}
-	body.List = append(body.List, &ast.ReturnStmt{Results: expression(fake)})
+	body.List = append(body.List, []ast.Stmt{
+		&ast.AssignStmt{
+			Lhs: expression(selectorExpr(fake, "opts")),
+			Tok: token.ASSIGN,
+			Rhs: expression(&ast.CallExpr{
+				Fun:      selectorExpr(ast.NewIdent("tablemock"), "NewOptions"),
+				Args:     expression(ast.NewIdent("opts")),
+				Ellipsis: 1,
+			}),
+		},
+		&ast.ReturnStmt{Results: expression(fake)},
+	}...)
 
-	funcName := "New" + strings.Title(ifce.Name)
-	params := fieldList()
-	results := fieldList(field(starExpr(ifce.Name)))
+	funcName := ifce.constructorName()
+	params := fieldList(field(&ast.Ellipsis{Elt: selectorExpr(ast.NewIdent("tablemock"), "Option")}, "opts"))
+	results := fieldList(field(ifce.fakeType()))
+
+	decl := funcDecl(nil, funcName, params, results, body)
+	decl.Type.TypeParams = ifce.typeParams()
 
-	return funcDecl(nil, funcName, params, results, body)
+	return decl
 }
 
-func (meth Method) generateReturns(ifceName string) *ast.FuncDecl {
+func (meth Method) generateReturns(ifce Interface) *ast.FuncDecl {
 	// Define resued ast.Nodes
 	fakeMethod := ast.NewIdent("fakeMethod")
 	fakeMethodField := selectorExpr(ast.NewIdent("fake"), meth.fieldName())
@@ -322,9 +438,9 @@ func (meth Method) generateReturns(ifceName string) *ast.FuncDecl {
 		},
 	}...)
 
-	recv := field(starExpr(strings.Title(ifceName)), "fake")
+	recv := ifce.recv()
 	name := strings.Title(meth.Name) + "Returns"
-	results := fieldList(field(starExpr(ifceName)))
+	results := fieldList(field(ifce.fakeType()))
 
 	return funcDecl(recv, name, params, results, body)
 }
@@ -345,8 +461,8 @@ func (val Value) assignToVar(method ast.Expr) *ast.AssignStmt {
 	}
 }
 
-func (meth Method) generateGetArgs(ifceName string) *ast.FuncDecl {
-	fakeMethodField := selectorExpr(ast.NewIdent("fake"), meth.fieldName())
+func (meth Method) generateGetArgs(ifce Interface) *ast.FuncDecl {
+	fakeMethodRecord := selectorExpr(ast.NewIdent("fake"), meth.recordName())
 	fakeMethodMutex := selectorExpr(ast.NewIdent("fake"), meth.mutexName())
 
 	body := blockStmt(&ast.ExprStmt{X: call(selectorExpr(fakeMethodMutex, "RLock"))})
@@ -357,31 +473,32 @@ func (meth Method) generateGetArgs(ifceName string) *ast.FuncDecl {
 		typ := resolveAssignType(arg.Type)
 		results.List = append(results.List, field(typ, arg.argName()))
 		returns = append(returns, ast.NewIdent(arg.argName()))
-		body.List = append(body.List, arg.assignToVar(valueIndex(fakeMethodField, "0")))
+		body.List = append(body.List, arg.assignToVar(valueIndex(fakeMethodRecord, "0")))
 	}
 	body.List = append(body.List, []ast.Stmt{
 		&ast.ExprStmt{X: call(selectorExpr(fakeMethodMutex, "RUnlock"))},
 		&ast.ReturnStmt{Results: returns},
 	}...)
 
-	recv := field(starExpr(strings.Title(ifceName)), "fake")
+	recv := ifce.recv()
 	funcName := strings.Title(meth.Name) + "GetArgs"
 	params := fieldList()
 
 	return funcDecl(recv, funcName, params, results, body)
 }
 
-func (meth Method) generateCallback(ifceName string) *ast.GenDecl {
-	fnIdent := ast.NewIdent(meth.structName(ifceName))
+func (meth Method) generateCallback(ifce Interface) *ast.GenDecl {
+	fnType := ifce.typeRef(meth.structName(ifce.fakeName()))
 
 	return &ast.GenDecl{
 		Tok: token.TYPE,
 		Specs: []ast.Spec{
 			&ast.TypeSpec{
-				Name: ast.NewIdent(meth.funcName(ifceName)),
+				Name:       ast.NewIdent(meth.funcName(ifce.fakeName())),
+				TypeParams: ifce.typeParams(),
 				Type: &ast.FuncType{
-					Params:  fieldList(field(fnIdent)),
-					Results: fieldList(field(fnIdent)),
+					Params:  fieldList(field(fnType)),
+					Results: fieldList(field(fnType)),
 				},
 			},
 		},
@@ -414,7 +531,7 @@ func (meth Method) assignToMap(index string, expr ast.Expr) *ast.AssignStmt {
 	}
 }
 
-func (meth Method) generateForCall(ifceName string) *ast.FuncDecl {
+func (meth Method) generateForCall(ifce Interface) *ast.FuncDecl {
 	fakeMethod := ast.NewIdent("fakeMethod")
 	fakeMethodMutex := selectorExpr(ast.NewIdent("fake"), meth.mutexName())
 
@@ -432,49 +549,90 @@ func (meth Method) generateForCall(ifceName string) *ast.FuncDecl {
 		&ast.ExprStmt{X: call(selectorExpr(fakeMethodMutex, "Unlock"))},
 		&ast.ReturnStmt{Results: expression(ast.NewIdent("fake"))},
 	)
-	recv := field(starExpr(strings.Title(ifceName)), "fake")
+	recv := ifce.recv()
 	funcName := strings.Title(meth.Name) + "ForCall"
 	params := fieldList(
 		field(ast.NewIdent("int"), "call"),
-		field(&ast.Ellipsis{Elt: ast.NewIdent(meth.funcName(ifceName))}, "fns"),
+		field(&ast.Ellipsis{Elt: ifce.typeRef(meth.funcName(ifce.fakeName()))}, "fns"),
 	)
-	results := fieldList(field(starExpr(ifceName)))
+	results := fieldList(field(ifce.fakeType()))
 
 	return funcDecl(recv, funcName, params, results, body)
 }
 
-func (ifce Interface) generateInterfaceStruct() ast.Decl {
+func (ifce Interface) generateInterfaceStruct(pkg string) ast.Decl {
 	fieldList := []*ast.Field{}
 	for _, method := range ifce.Methods {
 		methField := field(
-			simpleMap("int", method.structName(ifce.Name)),
+			ifce.methodMap(method),
 			method.fieldName(),
 		)
+		methRecord := field(
+			ifce.methodMap(method),
+			method.recordName(),
+		)
+		methWhen := field(
+			&ast.ArrayType{Elt: ifce.typeRef(method.whenStructName(ifce.fakeName()))},
+			method.whenFieldName(),
+		)
 		methMutex := field(
 			selectorExpr(ast.NewIdent("sync"), "RWMutex"),
 			method.mutexName(),
 		)
+		methGate := field(
+			selectorExpr(ast.NewIdent("tablemock"), "Gate"),
+			method.gateName(),
+		)
 		methRunCalls := field(
 			ast.NewIdent("int"),
 			method.callsName(),
 		)
 
-		fieldList = append(fieldList, methField, methMutex, methRunCalls)
+		fieldList = append(fieldList, spaced(methField).(*ast.Field), methRecord, methWhen, methMutex, methGate)
+		if _, ok := method.errorResult(); ok {
+			fieldList = append(fieldList,
+				field(selectorExpr(ast.NewIdent("tablemock"), "Every"), method.failsName()),
+				field(selectorExpr(ast.NewIdent("tablemock"), "Failures"), method.failuresName()),
+			)
+		}
+		fieldList = append(fieldList,
+			field(selectorExpr(ast.NewIdent("tablemock"), "Panics"), method.panicsName()),
+			field(durationType(), method.delayName()),
+			field(selectorExpr(ast.NewIdent("tablemock"), "Delays"), method.delaysName()),
+			field(selectorExpr(ast.NewIdent("tablemock"), "Sequence"), method.sequenceName()),
+		)
+		if method.setsArgs(ifce) {
+			fieldList = append(fieldList, field(selectorExpr(ast.NewIdent("tablemock"), "Sets"), method.setsName()))
+		}
+		fieldList = append(fieldList, methRunCalls)
+	}
+	opts := field(selectorExpr(ast.NewIdent("tablemock"), "Options"), "opts")
+	if ifce.spies() {
+		fieldList = append(fieldList, spaced(ifce.spyField(pkg)).(*ast.Field), opts)
+	} else {
+		fieldList = append(fieldList, spaced(opts).(*ast.Field))
 	}
 
-	return generateStruct(ifce.Name, fieldList)
+	return generateStruct(ifce.fakeName(), ifce.typeParams(), fieldList)
 }
 
-func (meth Method) generateMethodStruct(ifceName string) ast.Decl {
+func (meth Method) generateMethodStruct(ifce Interface) ast.Decl {
 	fieldList := []*ast.Field{}
 	for _, arg := range meth.Args {
 		fieldList = append(fieldList, arg.field())
 	}
+	if meth.takesContext() {
+		fieldList = append(fieldList, meth.deadlineField())
+	}
 	for _, res := range meth.Rets {
 		fieldList = append(fieldList, res.field())
 	}
+	fieldList = append(fieldList,
+		field(durationType(), delayField),
+		field(ast.NewIdent("interface{}"), panicField),
+	)
 
-	return generateStruct(toMethodStructName(ifceName, meth.Name), fieldList)
+	return generateStruct(meth.structName(ifce.fakeName()), ifce.typeParams(), fieldList)
 }
 
 func resolveAssignType(typ ast.Expr) ast.Expr {
@@ -495,7 +653,7 @@ func (value Value) field() *ast.Field {
 }
 
 func (value Value) variable() *ast.Field {
-	name := value.Name
+	name := value.argName()
 
 	return &ast.Field{
 		Names: []*ast.Ident{ast.NewIdent(name)},
@@ -508,12 +666,72 @@ func (value Value) fieldName() string {
 }
 
 func (value Value) argName() string {
+	if value.local != "" {
+		return value.local
+	}
 	return value.Name
 }
 
+// reservedNames are the names the generated methods taking the args or results
+// of a method as their own params use besides them: the receiver, fixed
+// params, locals and the builtins they call.
+var reservedNames = map[string]bool{
+	"fake": true, "fakeMethod": true, "configured": true, "fakeCall": true,
+	"fakeFailure": true, "fakeFailed": true, "fakeSets": true, "fakeCtxErr": true,
+	"value": true, "ok": true, "delay": true, "when": true, "matchers": true,
+	"t": true, "call": true, "calls": true, "k": true, "fns": true, "fn": true,
+	"append": true, "bool": true, "delete": true, "error": true, "int": true,
+	"len": true, "make": true, "nil": true, "panic": true, "string": true,
+}
+
+// withLocalNames returns ifce with every arg and result whose name clashes
+// with reservedNames or the packages the fake imports renamed in the bodies of
+// the fake, by appending Arg or Result until it's free. The field names keep
+// the names of the source, so Find(match string) still records Match.
+func (ifce Interface) withLocalNames(pkg string) Interface {
+	reserved := map[string]bool{ifce.Package: true}
+	for name := range reservedNames {
+		reserved[name] = true
+	}
+	for _, imp := range ifce.imports(pkg) {
+		reserved[path.Base(imp)] = true
+	}
+
+	methods := make([]Method, len(ifce.Methods))
+	for i, meth := range ifce.Methods {
+		taken := make(map[string]bool)
+		for _, val := range append(append([]Value{}, meth.Args...), meth.Rets...) {
+			taken[val.Name] = true
+		}
+		rename := func(vals []Value, suffix string) []Value {
+			renamed := append([]Value(nil), vals...)
+			for j, val := range renamed {
+				if !reserved[val.Name] {
+					continue
+				}
+				name := val.Name + suffix
+				for reserved[name] || taken[name] {
+					name += suffix
+				}
+				taken[name] = true
+				renamed[j].local = name
+			}
+			return renamed
+		}
+		meth.Args, meth.Rets = rename(meth.Args, "Arg"), rename(meth.Rets, "Result")
+		methods[i] = meth
+	}
+	ifce.Methods = methods
+
+	return ifce
+}
+
 func (method Method) fieldName() string {
 	return toMethodName(method.Name, "Method")
 }
+func (method Method) recordName() string {
+	return toMethodName(method.Name, "Record")
+}
 func (method Method) mutexName() string {
 	return toMethodName(method.Name, "Mutex")
 }
@@ -521,12 +739,12 @@ func (method Method) callsName() string {
 	return strings.Title(method.Name) + "Calls"
 }
 
-func (method Method) structName(ifceName string) string {
-	return strings.Title(ifceName) + strings.Title(method.Name) + "Method"
+func (method Method) structName(fakeName string) string {
+	return fakeName + strings.Title(method.Name) + "Method"
 }
 
-func (method Method) funcName(ifceName string) string {
-	return strings.Title(ifceName) + strings.Title(method.Name) + "Func"
+func (method Method) funcName(fakeName string) string {
+	return fakeName + strings.Title(method.Name) + "Func"
 }
 
 func toMethodName(name, suffix string) string {
@@ -538,17 +756,14 @@ func toMethodName(name, suffix string) string {
 
 }
 
-func toMethodStructName(ifceName, methodName string) string {
-	return strings.Title(ifceName) + strings.Title(methodName) + "Method"
-}
-
-func generateStruct(name string, fieldList []*ast.Field) *ast.GenDecl {
+func generateStruct(name string, typeParams *ast.FieldList, fieldList []*ast.Field) *ast.GenDecl {
 	return &ast.GenDecl{
 		Tok: token.TYPE,
 		Specs: []ast.Spec{
 			&ast.TypeSpec{
-				Name: ast.NewIdent(name),
-				Type: &ast.StructType{Fields: &ast.FieldList{List: fieldList}},
+				Name:       ast.NewIdent(name),
+				TypeParams: typeParams,
+				Type:       &ast.StructType{Fields: &ast.FieldList{List: fieldList}},
 			},
 		},
 	}
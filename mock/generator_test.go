@@ -75,120 +75,6 @@ func expectReader(expected io.Reader) checkReader {
 	}
 }
 
-func TestGenerateFile(t *testing.T) {
-	check := func(fns ...checkReader) []checkReader { return fns }
-
-	tests := [...]struct {
-		name   string
-		input  *Interface
-		checks []checkReader
-	}{
-		{
-			"Simple generate",
-			newTestInterface("Runner").
-				WithMethod(newTestMethod("Run").
-					WithArg(newTestValue("distanceArg")).
-					WithRet(newTestValue("durationResult").asDuration()),
-				).
-				WithImport("time").
-				ToInterface(),
-			check(expectReader(strings.NewReader(`
-// generated by table-mocks; DO NOT EDIT
-
-package fake
-
-import (
-	"sync"
-	"time"
-)
-
-type Runner struct {
-	runMethod map[int]RunnerRunMethod
-	runMutex  sync.RWMutex
-	RunCalls  int
-}
-
-type RunnerRunMethod struct {
-	DistanceArg    string
-	DurationResult time.Duration
-}
-
-func NewRunner() *Runner {
-	fake := &Runner{}
-	fake.runMethod = make(map[int]RunnerRunMethod)
-
-	return fake
-}
-
-func (fake *Runner) Run(distanceArg string) (durationResult time.Duration) {
-	fake.runMutex.Lock()
-	fakeMethod := fake.runMethod[fake.RunCalls]
-	fakeMethod.DistanceArg = distanceArg
-	fake.runMethod[fake.RunCalls] = fakeMethod
-	fake.RunCalls++
-	fake.runMutex.Unlock()
-
-	return fakeMethod.DurationResult
-}
-
-func (fake *Runner) RunReturns(durationResult time.Duration) *Runner {
-	fake.runMutex.Lock()
-	fakeMethod := fake.runMethod[0]
-	fakeMethod.DurationResult = durationResult
-	fake.runMethod[0] = fakeMethod
-	fake.runMutex.Unlock()
-
-	return fake
-}
-
-func (fake *Runner) RunGetArgs() (distanceArg string) {
-	fake.runMutex.RLock()
-	distanceArg = fake.runMethod[0].DistanceArg
-	fake.runMutex.RUnlock()
-
-	return distanceArg
-}
-
-type RunnerRunFunc func(RunnerRunMethod) RunnerRunMethod
-
-func (fake *Runner) RunForCall(call int, fns ...RunnerRunFunc) *Runner {
-	fake.runMutex.Lock()
-	for _, fn := range fns {
-		fakeMethod := fake.runMethod[call]
-		fake.runMethod[call] = fn(fakeMethod)
-	}
-	fake.runMutex.Unlock()
-
-	return fake
-}
-`,
-			))),
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			f, err := ioutil.TempFile("", "generate_file_")
-			if err != nil {
-				t.Fatalf("error creating tempfile: %v", err)
-			}
-			defer os.Remove(f.Name())
-
-			err = GenerateFile(tt.input, "simple", f)
-			f.Close()
-			for _, check := range tt.checks {
-				f, _ = os.Open(f.Name())
-				defer f.Close()
-				for _, checkErr := range check(f) {
-					if checkErr != nil {
-						t.Error(checkErr)
-					}
-				}
-			}
-		})
-	}
-}
-
 func TestGenerateStructs(t *testing.T) {
 	check := func(fns ...checkReader) []checkReader { return fns }
 
@@ -228,14 +114,26 @@ func TestGenerateStructs(t *testing.T) {
 			check(
 				expectReader(strings.NewReader(`
 type Runner struct {
-	runMethod map[int]RunnerRunMethod
-	runMutex  sync.RWMutex
-	RunCalls  int
+	runMethod   map[int]RunnerRunMethod
+	runRecord   map[int]RunnerRunMethod
+	runWhen     []RunnerRunWhen
+	runMutex    sync.RWMutex
+	runGate     tablemock.Gate
+	runFails    tablemock.Every
+	runFailures tablemock.Failures
+	runPanics   tablemock.Panics
+	runDelay    time.Duration
+	runDelays   tablemock.Delays
+	runSequence tablemock.Sequence
+	RunCalls    int
+	opts        tablemock.Options
 }
 type RunnerRunMethod struct {
 	DistanceArg    int
 	DurationResult time.Duration
 	ErrResult      error
+	DelayValue     time.Duration
+	PanicValue     interface{}
 }`,
 				)),
 			),
@@ -250,7 +148,7 @@ type RunnerRunMethod struct {
 			}
 			defer os.Remove(f.Name())
 
-			decls := tt.input.GenerateStructs()
+			decls := tt.input.GenerateStructs("fake")
 			if err := format.Node(f, token.NewFileSet(), decls); err != nil {
 				t.Fatalf("error writing node: %v", err)
 			}
@@ -307,18 +205,162 @@ func TestGenerateMethods(t *testing.T) {
 			},
 			check(
 				expectReader(strings.NewReader(
-					`func NewRunner() *Runner {
+					`func NewRunner(opts ...tablemock.Option) *Runner {
 	fake := &Runner{}
 	fake.runMethod = make(map[int]RunnerRunMethod)
+	fake.runRecord = make(map[int]RunnerRunMethod)
+	fake.opts = tablemock.NewOptions(opts...)
 	return fake
 }
+func (fake *Runner) Calls() []tablemock.Call {
+	return fake.opts.Recorder.CallsFor(fake)
+}
+func (fake *Runner) Reset() {
+	fake.runMutex.Lock()
+	fake.runMethod = make(map[int]RunnerRunMethod)
+	fake.runRecord = make(map[int]RunnerRunMethod)
+	fake.runWhen = nil
+	fake.runFails = tablemock.Every{}
+	fake.runFailures = nil
+	fake.runPanics = nil
+	fake.runDelay = 0
+	fake.runDelays = nil
+	fake.runSequence = tablemock.Sequence{}
+	fake.RunCalls = 0
+	fake.runGate.Count(fake.RunCalls)
+	fake.runMutex.Unlock()
+	fake.runGate.Release()
+	fake.opts.Recorder.Reset(fake)
+}
+func (fake *Runner) ResetCalls() {
+	fake.runMutex.Lock()
+	fake.runRecord = make(map[int]RunnerRunMethod)
+	fake.RunCalls = 0
+	fake.runGate.Count(fake.RunCalls)
+	fake.runMutex.Unlock()
+	fake.opts.Recorder.Reset(fake)
+}
+
+type RunnerSnapshot struct {
+	runMethod   map[int]RunnerRunMethod
+	runRecord   map[int]RunnerRunMethod
+	runWhen     []RunnerRunWhen
+	runFails    tablemock.Every
+	runFailures tablemock.Failures
+	runPanics   tablemock.Panics
+	runDelay    time.Duration
+	runDelays   tablemock.Delays
+	runSequence tablemock.Sequence
+	runCalls    int
+	calls       []tablemock.Call
+}
+
+func (fake *Runner) Snapshot() RunnerSnapshot {
+	snapshot := RunnerSnapshot{calls: fake.Calls()}
+	fake.runMutex.RLock()
+	snapshot.runMethod = make(map[int]RunnerRunMethod, len(fake.runMethod))
+	for call, fakeMethod := range fake.runMethod {
+		snapshot.runMethod[call] = fakeMethod
+	}
+	snapshot.runRecord = make(map[int]RunnerRunMethod, len(fake.runRecord))
+	for call, fakeMethod := range fake.runRecord {
+		snapshot.runRecord[call] = fakeMethod
+	}
+	snapshot.runWhen = append([]RunnerRunWhen(nil), fake.runWhen...)
+	snapshot.runFails = fake.runFails
+	snapshot.runFailures = fake.runFailures
+	snapshot.runPanics = fake.runPanics
+	snapshot.runDelay = fake.runDelay
+	snapshot.runDelays = fake.runDelays
+	snapshot.runSequence = fake.runSequence
+	snapshot.runCalls = fake.RunCalls
+	fake.runMutex.RUnlock()
+	return snapshot
+}
+func (fake *Runner) Restore(snapshot RunnerSnapshot) {
+	fake.runMutex.Lock()
+	fake.runMethod = make(map[int]RunnerRunMethod, len(snapshot.runMethod))
+	for call, fakeMethod := range snapshot.runMethod {
+		fake.runMethod[call] = fakeMethod
+	}
+	fake.runRecord = make(map[int]RunnerRunMethod, len(snapshot.runRecord))
+	for call, fakeMethod := range snapshot.runRecord {
+		fake.runRecord[call] = fakeMethod
+	}
+	fake.runWhen = append([]RunnerRunWhen(nil), snapshot.runWhen...)
+	fake.runFails = snapshot.runFails
+	fake.runFailures = snapshot.runFailures
+	fake.runPanics = snapshot.runPanics
+	fake.runDelay = snapshot.runDelay
+	fake.runDelays = snapshot.runDelays
+	fake.runSequence = snapshot.runSequence
+	fake.RunCalls = snapshot.runCalls
+	fake.runGate.Count(fake.RunCalls)
+	fake.runMutex.Unlock()
+	fake.opts.Recorder.Reset(fake, snapshot.calls...)
+}
+func (fake *Runner) Record(w io.Writer) error {
+	snapshot := fake.Snapshot()
+	return tablemock.WriteRecording(w, map[string]interface{}{"Runner.Run": snapshot.runRecord})
+}
+func (fake *Runner) LoadReplay(r io.Reader) error {
+	runMethod := make(map[int]RunnerRunMethod)
+	if err := tablemock.ReadRecording(r, map[string]interface{}{"Runner.Run": runMethod}); err != nil {
+		return err
+	}
+	fake.runMutex.Lock()
+	for call, fakeMethod := range runMethod {
+		fake.runMethod[call] = fakeMethod
+	}
+	fake.runMutex.Unlock()
+	return nil
+}
 func (fake *Runner) Run(distanceArg int) (durationResult time.Duration, errResult error) {
 	fake.runMutex.Lock()
-	fakeMethod := fake.runMethod[fake.RunCalls]
+	fakeMethod, configured := fake.runMethod[fake.RunCalls]
+	if !configured {
+		fakeMethod, configured = fake.runMethod[fake.runSequence.Index(fake.RunCalls)]
+	}
 	fakeMethod.DistanceArg = distanceArg
-	fake.runMethod[fake.RunCalls] = fakeMethod
+	for _, when := range fake.runWhen {
+		if match.Args(when.matchers, distanceArg) {
+			fakeMethod.DurationResult = when.method.DurationResult
+			fakeMethod.ErrResult = when.method.ErrResult
+			configured = true
+			break
+		}
+	}
+	fakeFailure, fakeFailed := fake.runFailures[fake.RunCalls]
+	if !fakeFailed {
+		fakeFailure, fakeFailed = fake.runSequence.Fails(fake.RunCalls)
+	}
+	if !fakeFailed {
+		fakeFailure, fakeFailed = fake.runFails.Fails(fake.RunCalls)
+	}
+	if fakeFailed {
+		fakeMethod.ErrResult = fakeFailure
+	}
+	if value, ok := fake.runPanics[fake.RunCalls]; ok {
+		fakeMethod.PanicValue = value
+	}
+	if delay, ok := fake.runDelays[fake.RunCalls]; ok {
+		fakeMethod.DelayValue = delay
+	} else if fakeMethod.DelayValue == 0 {
+		fakeMethod.DelayValue = fake.runDelay
+	}
+	fake.runRecord[fake.RunCalls] = fakeMethod
+	fakeCall := fake.opts.Recorder.Record(fake, "Runner.Run", fake.RunCalls, distanceArg)
 	fake.RunCalls++
+	fake.runGate.Count(fake.RunCalls)
 	fake.runMutex.Unlock()
+	fake.runGate.Pass(nil)
+	fake.opts.Sleep(nil, fakeMethod.DelayValue)
+	if fakeMethod.PanicValue != nil {
+		panic(fakeMethod.PanicValue)
+	}
+	if !configured && !fakeFailed {
+		fake.opts.Unexpected(fakeCall)
+	}
 	return fakeMethod.DurationResult, fakeMethod.ErrResult
 }
 func (fake *Runner) RunReturns(durationResult time.Duration, errResult error) *Runner {
@@ -332,7 +374,7 @@ func (fake *Runner) RunReturns(durationResult time.Duration, errResult error) *R
 }
 func (fake *Runner) RunGetArgs() (distanceArg int) {
 	fake.runMutex.RLock()
-	distanceArg = fake.runMethod[0].DistanceArg
+	distanceArg = fake.runRecord[0].DistanceArg
 	fake.runMutex.RUnlock()
 	return distanceArg
 }
@@ -347,6 +389,117 @@ func (fake *Runner) RunForCall(call int, fns ...RunnerRunFunc) *Runner {
 	}
 	fake.runMutex.Unlock()
 	return fake
+}
+
+type RunnerRunWhen struct {
+	fake     *Runner
+	matchers []match.Matcher
+	method   RunnerRunMethod
+}
+
+func (fake *Runner) RunWhen(matchers ...match.Matcher) *RunnerRunWhen {
+	return &RunnerRunWhen{fake: fake, matchers: matchers}
+}
+func (when *RunnerRunWhen) Returns(durationResult time.Duration, errResult error) *Runner {
+	when.method.DurationResult = durationResult
+	when.method.ErrResult = errResult
+	when.fake.runMutex.Lock()
+	when.fake.runWhen = append(when.fake.runWhen, *when)
+	when.fake.runMutex.Unlock()
+	return when.fake
+}
+func (fake *Runner) RunBlock() *Runner {
+	fake.runGate.Block()
+	return fake
+}
+func (fake *Runner) RunRelease() {
+	fake.runGate.Release()
+}
+func (fake *Runner) RunWaitForCalls(ctx context.Context, n int) error {
+	return fake.runGate.WaitForCalls(ctx, n)
+}
+func (fake *Runner) RunPanicsOnCall(call int, value interface{}) *Runner {
+	fake.runMutex.Lock()
+	fake.runPanics = fake.runPanics.With(call, value)
+	fake.runMutex.Unlock()
+	return fake
+}
+func (fake *Runner) RunFailsOnCall(call int, errResult error) *Runner {
+	fake.runMutex.Lock()
+	fake.runFailures = fake.runFailures.With(call, errResult)
+	fake.runMutex.Unlock()
+	return fake
+}
+func (fake *Runner) RunFailsEvery(k int, errResult error) *Runner {
+	fake.runMutex.Lock()
+	fake.runFails = tablemock.Every{K: k, Err: errResult}
+	fake.runMutex.Unlock()
+	return fake
+}
+func (fake *Runner) RunDelay(delay time.Duration) *Runner {
+	fake.runMutex.Lock()
+	fake.runDelay = delay
+	fake.runMutex.Unlock()
+	return fake
+}
+func (fake *Runner) RunDelayOnCall(call int, delay time.Duration) *Runner {
+	fake.runMutex.Lock()
+	fake.runDelays = fake.runDelays.With(call, delay)
+	fake.runMutex.Unlock()
+	return fake
+}
+func (fake *Runner) RunReturnsSequence(fakeMethods ...RunnerRunMethod) *Runner {
+	fake.runMutex.Lock()
+	for call := 0; call < fake.runSequence.Len; call++ {
+		delete(fake.runMethod, call)
+	}
+	for call, fakeMethod := range fakeMethods {
+		fake.runMethod[call] = fakeMethod
+	}
+	fake.runSequence.Len = len(fakeMethods)
+	fake.runMutex.Unlock()
+	return fake
+}
+func (fake *Runner) RunSequenceEnd(end tablemock.SequenceEnd) *Runner {
+	fake.runMutex.Lock()
+	fake.runSequence.End = end
+	fake.runMutex.Unlock()
+	return fake
+}
+func (fake *Runner) RunForCallRange(from, to int, fns ...RunnerRunFunc) *Runner {
+	for call := from; call < to; call++ {
+		fake.RunForCall(call, fns...)
+	}
+	return fake
+}
+func (fake *Runner) AssertRunCalled(t testing.TB) {
+	t.Helper()
+	fake.runMutex.RLock()
+	calls := fake.RunCalls
+	fake.runMutex.RUnlock()
+	tablemock.AssertCalled(t, "Runner.Run", calls)
+}
+func (fake *Runner) AssertRunCalledTimes(t testing.TB, times int) {
+	t.Helper()
+	fake.runMutex.RLock()
+	calls := fake.RunCalls
+	fake.runMutex.RUnlock()
+	tablemock.AssertCalledTimes(t, "Runner.Run", times, calls)
+}
+func (fake *Runner) AssertRunCalledWith(t testing.TB, call int, distanceArg int) {
+	t.Helper()
+	fake.runMutex.RLock()
+	fakeMethod := fake.runRecord[call]
+	calls := fake.RunCalls
+	fake.runMutex.RUnlock()
+	tablemock.AssertCalledWith(t, "Runner.Run", call, calls, []string{"distanceArg"}, []interface{}{distanceArg}, []interface{}{fakeMethod.DistanceArg})
+}
+func (fake *Runner) AssertRunNotCalled(t testing.TB) {
+	t.Helper()
+	fake.runMutex.RLock()
+	calls := fake.RunCalls
+	fake.runMutex.RUnlock()
+	tablemock.AssertNotCalled(t, "Runner.Run", calls)
 }`,
 				)),
 			),
@@ -361,7 +514,7 @@ func (fake *Runner) RunForCall(call int, fns ...RunnerRunFunc) *Runner {
 			}
 			defer os.Remove(f.Name())
 
-			decls := tt.input.GenerateMethods()
+			decls := tt.input.GenerateMethods("fake")
 			if err := format.Node(f, token.NewFileSet(), decls); err != nil {
 				t.Fatalf("error writing node: %v", err)
 			}
@@ -393,6 +546,7 @@ func TestGenerateInterfaceStruct(t *testing.T) {
 			newTestInterface("Runner").ToInterface(),
 			check(expectReader(strings.NewReader(`
 type Runner struct {
+	opts tablemock.Options
 }
 `,
 			))),
@@ -404,13 +558,29 @@ type Runner struct {
 				ToInterface(),
 			check(expectReader(strings.NewReader(`
 type Runner struct {
-	runMethod map[int]RunnerRunMethod
-	runMutex  sync.RWMutex
-	RunCalls  int
+	runMethod   map[int]RunnerRunMethod
+	runRecord   map[int]RunnerRunMethod
+	runWhen     []RunnerRunWhen
+	runMutex    sync.RWMutex
+	runGate     tablemock.Gate
+	runPanics   tablemock.Panics
+	runDelay    time.Duration
+	runDelays   tablemock.Delays
+	runSequence tablemock.Sequence
+	RunCalls    int
 
-	walkMethod map[int]RunnerWalkMethod
-	walkMutex  sync.RWMutex
-	WalkCalls  int
+	walkMethod   map[int]RunnerWalkMethod
+	walkRecord   map[int]RunnerWalkMethod
+	walkWhen     []RunnerWalkWhen
+	walkMutex    sync.RWMutex
+	walkGate     tablemock.Gate
+	walkPanics   tablemock.Panics
+	walkDelay    time.Duration
+	walkDelays   tablemock.Delays
+	walkSequence tablemock.Sequence
+	WalkCalls    int
+
+	opts tablemock.Options
 }
 `,
 			))),
@@ -444,6 +614,8 @@ func TestGenerateMethodStruct(t *testing.T) {
 			newTestMethod("Run").ToMethod(),
 			check(expectReader(strings.NewReader(`
 type RunnerRunMethod struct {
+	DelayValue time.Duration
+	PanicValue interface{}
 }
 `,
 			))),
@@ -458,6 +630,8 @@ type RunnerRunMethod struct {
 type RunnerRunMethod struct {
 	DistanceArg string
 	TimeResult  string
+	DelayValue  time.Duration
+	PanicValue  interface{}
 }
 `,
 			))),
@@ -472,6 +646,8 @@ type RunnerRunMethod struct {
 type RunnerRunMethod struct {
 	DistanceArg []string
 	TimeResult  string
+	DelayValue  time.Duration
+	PanicValue  interface{}
 }
 `,
 			))),
@@ -506,10 +682,37 @@ func TestGenerateMethodFunc(t *testing.T) {
 			check(expectReader(strings.NewReader(`
 func (fake *Runner) Run() {
 	fake.runMutex.Lock()
-	fakeMethod := fake.runMethod[fake.RunCalls]
-	fake.runMethod[fake.RunCalls] = fakeMethod
+	fakeMethod, configured := fake.runMethod[fake.RunCalls]
+	if !configured {
+		fakeMethod, configured = fake.runMethod[fake.runSequence.Index(fake.RunCalls)]
+	}
+	for _, when := range fake.runWhen {
+		if match.Args(when.matchers) {
+			configured = true
+			break
+		}
+	}
+	if value, ok := fake.runPanics[fake.RunCalls]; ok {
+		fakeMethod.PanicValue = value
+	}
+	if delay, ok := fake.runDelays[fake.RunCalls]; ok {
+		fakeMethod.DelayValue = delay
+	} else if fakeMethod.DelayValue == 0 {
+		fakeMethod.DelayValue = fake.runDelay
+	}
+	fake.runRecord[fake.RunCalls] = fakeMethod
+	fakeCall := fake.opts.Recorder.Record(fake, "Runner.Run", fake.RunCalls)
 	fake.RunCalls++
+	fake.runGate.Count(fake.RunCalls)
 	fake.runMutex.Unlock()
+	fake.runGate.Pass(nil)
+	fake.opts.Sleep(nil, fakeMethod.DelayValue)
+	if fakeMethod.PanicValue != nil {
+		panic(fakeMethod.PanicValue)
+	}
+	if !configured {
+		fake.opts.Unexpected(fakeCall)
+	}
 
 	return
 }
@@ -525,11 +728,39 @@ func (fake *Runner) Run() {
 			check(expectReader(strings.NewReader(`
 func (fake *Runner) Run(distanceArg string) (timeResult string) {
 	fake.runMutex.Lock()
-	fakeMethod := fake.runMethod[fake.RunCalls]
+	fakeMethod, configured := fake.runMethod[fake.RunCalls]
+	if !configured {
+		fakeMethod, configured = fake.runMethod[fake.runSequence.Index(fake.RunCalls)]
+	}
 	fakeMethod.DistanceArg = distanceArg
-	fake.runMethod[fake.RunCalls] = fakeMethod
+	for _, when := range fake.runWhen {
+		if match.Args(when.matchers, distanceArg) {
+			fakeMethod.TimeResult = when.method.TimeResult
+			configured = true
+			break
+		}
+	}
+	if value, ok := fake.runPanics[fake.RunCalls]; ok {
+		fakeMethod.PanicValue = value
+	}
+	if delay, ok := fake.runDelays[fake.RunCalls]; ok {
+		fakeMethod.DelayValue = delay
+	} else if fakeMethod.DelayValue == 0 {
+		fakeMethod.DelayValue = fake.runDelay
+	}
+	fake.runRecord[fake.RunCalls] = fakeMethod
+	fakeCall := fake.opts.Recorder.Record(fake, "Runner.Run", fake.RunCalls, distanceArg)
 	fake.RunCalls++
+	fake.runGate.Count(fake.RunCalls)
 	fake.runMutex.Unlock()
+	fake.runGate.Pass(nil)
+	fake.opts.Sleep(nil, fakeMethod.DelayValue)
+	if fakeMethod.PanicValue != nil {
+		panic(fakeMethod.PanicValue)
+	}
+	if !configured {
+		fake.opts.Unexpected(fakeCall)
+	}
 
 	return fakeMethod.TimeResult
 }
@@ -545,11 +776,39 @@ func (fake *Runner) Run(distanceArg string) (timeResult string) {
 			check(expectReader(strings.NewReader(`
 func (fake *Runner) Run(distanceArg ...string) (timeResult string) {
 	fake.runMutex.Lock()
-	fakeMethod := fake.runMethod[fake.RunCalls]
+	fakeMethod, configured := fake.runMethod[fake.RunCalls]
+	if !configured {
+		fakeMethod, configured = fake.runMethod[fake.runSequence.Index(fake.RunCalls)]
+	}
 	fakeMethod.DistanceArg = distanceArg
-	fake.runMethod[fake.RunCalls] = fakeMethod
+	for _, when := range fake.runWhen {
+		if match.Args(when.matchers, distanceArg) {
+			fakeMethod.TimeResult = when.method.TimeResult
+			configured = true
+			break
+		}
+	}
+	if value, ok := fake.runPanics[fake.RunCalls]; ok {
+		fakeMethod.PanicValue = value
+	}
+	if delay, ok := fake.runDelays[fake.RunCalls]; ok {
+		fakeMethod.DelayValue = delay
+	} else if fakeMethod.DelayValue == 0 {
+		fakeMethod.DelayValue = fake.runDelay
+	}
+	fake.runRecord[fake.RunCalls] = fakeMethod
+	fakeCall := fake.opts.Recorder.Record(fake, "Runner.Run", fake.RunCalls, distanceArg)
 	fake.RunCalls++
+	fake.runGate.Count(fake.RunCalls)
 	fake.runMutex.Unlock()
+	fake.runGate.Pass(nil)
+	fake.opts.Sleep(nil, fakeMethod.DelayValue)
+	if fakeMethod.PanicValue != nil {
+		panic(fakeMethod.PanicValue)
+	}
+	if !configured {
+		fake.opts.Unexpected(fakeCall)
+	}
 
 	return fakeMethod.TimeResult
 }
@@ -602,7 +861,7 @@ func (fake *Runner) RunGetArgs() {
 			check(expectReader(strings.NewReader(`
 func (fake *Runner) RunGetArgs() (distanceArg string) {
 	fake.runMutex.RLock()
-	distanceArg = fake.runMethod[0].DistanceArg
+	distanceArg = fake.runRecord[0].DistanceArg
 	fake.runMutex.RUnlock()
 
 	return distanceArg
@@ -619,7 +878,7 @@ func (fake *Runner) RunGetArgs() (distanceArg string) {
 			check(expectReader(strings.NewReader(`
 func (fake *Runner) RunGetArgs() (distanceArg []string) {
 	fake.runMutex.RLock()
-	distanceArg = fake.runMethod[0].DistanceArg
+	distanceArg = fake.runRecord[0].DistanceArg
 	fake.runMutex.RUnlock()
 
 	return distanceArg
@@ -640,3 +899,267 @@ func (fake *Runner) RunGetArgs() (distanceArg []string) {
 		}
 	}
 }
+
+func TestGenerateMethodAsserts(t *testing.T) {
+	check := func(fns ...checkReader) []checkReader { return fns }
+
+	tests := [...]struct {
+		name   string
+		ifce   string
+		meth   Method
+		checks []checkReader
+	}{
+		{
+			"Basic method",
+			"Runner",
+			newTestMethod("Run").ToMethod(),
+			check(expectReader(strings.NewReader(`
+func (fake *Runner) AssertRunCalled(t testing.TB) {
+	t.Helper()
+	fake.runMutex.RLock()
+	calls := fake.RunCalls
+	fake.runMutex.RUnlock()
+
+	tablemock.AssertCalled(t, "Runner.Run", calls)
+}
+
+func (fake *Runner) AssertRunCalledTimes(t testing.TB, times int) {
+	t.Helper()
+	fake.runMutex.RLock()
+	calls := fake.RunCalls
+	fake.runMutex.RUnlock()
+
+	tablemock.AssertCalledTimes(t, "Runner.Run", times, calls)
+}
+
+func (fake *Runner) AssertRunCalledWith(t testing.TB, call int) {
+	t.Helper()
+	fake.runMutex.RLock()
+	calls := fake.RunCalls
+	fake.runMutex.RUnlock()
+
+	tablemock.AssertCalledWith(t, "Runner.Run", call, calls, []string{}, []interface{}{}, []interface{}{})
+}
+
+func (fake *Runner) AssertRunNotCalled(t testing.TB) {
+	t.Helper()
+	fake.runMutex.RLock()
+	calls := fake.RunCalls
+	fake.runMutex.RUnlock()
+
+	tablemock.AssertNotCalled(t, "Runner.Run", calls)
+}
+`,
+			))),
+		}, {
+			"With variadic params",
+			"Runner",
+			newTestMethod("Run").
+				WithArg(newTestValue("distanceArg")).
+				WithArg(newTestValue("pacesArg").asEllipse()).
+				ToMethod(),
+			check(expectReader(strings.NewReader(`
+func (fake *Runner) AssertRunCalled(t testing.TB) {
+	t.Helper()
+	fake.runMutex.RLock()
+	calls := fake.RunCalls
+	fake.runMutex.RUnlock()
+
+	tablemock.AssertCalled(t, "Runner.Run", calls)
+}
+
+func (fake *Runner) AssertRunCalledTimes(t testing.TB, times int) {
+	t.Helper()
+	fake.runMutex.RLock()
+	calls := fake.RunCalls
+	fake.runMutex.RUnlock()
+
+	tablemock.AssertCalledTimes(t, "Runner.Run", times, calls)
+}
+
+func (fake *Runner) AssertRunCalledWith(t testing.TB, call int, distanceArg string, pacesArg ...string) {
+	t.Helper()
+	fake.runMutex.RLock()
+	fakeMethod := fake.runRecord[call]
+	calls := fake.RunCalls
+	fake.runMutex.RUnlock()
+
+	tablemock.AssertCalledWith(t, "Runner.Run", call, calls, []string{"distanceArg", "pacesArg"}, []interface{}{distanceArg, pacesArg}, []interface{}{fakeMethod.DistanceArg, fakeMethod.PacesArg})
+}
+
+func (fake *Runner) AssertRunNotCalled(t testing.TB) {
+	t.Helper()
+	fake.runMutex.RLock()
+	calls := fake.RunCalls
+	fake.runMutex.RUnlock()
+
+	tablemock.AssertNotCalled(t, "Runner.Run", calls)
+}
+`,
+			))),
+		},
+	}
+
+	for _, tt := range tests {
+		output := GenerateMethodAsserts(tt.ifce, tt.meth)
+		for _, check := range tt.checks {
+			for _, checkErr := range check(strings.NewReader(output)) {
+				if checkErr != nil {
+					t.Error(checkErr)
+				}
+			}
+		}
+	}
+}
+
+func TestGenerateMethodWhen(t *testing.T) {
+	check := func(fns ...checkReader) []checkReader { return fns }
+
+	tests := [...]struct {
+		name   string
+		ifce   string
+		meth   Method
+		checks []checkReader
+	}{
+		{
+			"Simple params",
+			"Runner",
+			newTestMethod("Run").
+				WithArg(newTestValue("distanceArg")).
+				WithRet(newTestValue("timeResult")).
+				ToMethod(),
+			check(expectReader(strings.NewReader(`
+type RunnerRunWhen struct {
+	fake     *Runner
+	matchers []match.Matcher
+	method   RunnerRunMethod
+}
+
+func (fake *Runner) RunWhen(matchers ...match.Matcher) *RunnerRunWhen {
+	return &RunnerRunWhen{fake: fake, matchers: matchers}
+}
+
+func (when *RunnerRunWhen) Returns(timeResult string) *Runner {
+	when.method.TimeResult = timeResult
+	when.fake.runMutex.Lock()
+	when.fake.runWhen = append(when.fake.runWhen, *when)
+	when.fake.runMutex.Unlock()
+
+	return when.fake
+}
+`,
+			))),
+		},
+	}
+
+	for _, tt := range tests {
+		output := GenerateMethodWhen(tt.ifce, tt.meth)
+		for _, check := range tt.checks {
+			for _, checkErr := range check(strings.NewReader(output)) {
+				if checkErr != nil {
+					t.Error(checkErr)
+				}
+			}
+		}
+	}
+}
+
+func TestGenerateFunc(t *testing.T) {
+	check := func(fns ...checkReader) []checkReader { return fns }
+	handler := func() testInterface {
+		return newTestInterface("Handler").WithMethod(newTestMethod("Call").
+			WithArg(newTestValue("req")).
+			WithRet(newTestValue("errResult"))).asFunc()
+	}
+
+	tests := [...]struct {
+		name   string
+		ifce   *Interface
+		checks []checkReader
+	}{
+		{
+			"Unknown source package",
+			handler().ToInterface(),
+			check(expectReader(strings.NewReader(`
+func (fake *Handler) Func() func(req string) (errResult string) {
+	return fake.Call
+}
+`,
+			))),
+		}, {
+			"Separate package",
+			handler().WithSource("design", "example.com/design").ToInterface(),
+			check(expectReader(strings.NewReader(`
+func (fake *Handler) Func() design.Handler {
+	return fake.Call
+}
+`,
+			))),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := GenerateFunc(tt.ifce, "fake")
+			for _, check := range tt.checks {
+				for _, checkErr := range check(strings.NewReader(output)) {
+					if checkErr != nil {
+						t.Error(checkErr)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateConformance(t *testing.T) {
+	check := func(fns ...checkReader) []checkReader { return fns }
+
+	tests := [...]struct {
+		name   string
+		ifce   *Interface
+		pkg    string
+		checks []checkReader
+	}{
+		{
+			"Unknown source package",
+			newTestInterface("Runner").ToInterface(),
+			"fake",
+			check(expectReader(strings.NewReader(``))),
+		}, {
+			"Separate package",
+			&Interface{Name: "Runner", Package: "design", PkgPath: "example.com/design"},
+			"fake",
+			check(expectReader(strings.NewReader(`
+var _ design.Runner = (*Runner)(nil)
+`,
+			))),
+		}, {
+			"Same package",
+			&Interface{Name: "Runner", Package: "design", PkgPath: "example.com/design"},
+			"design",
+			check(expectReader(strings.NewReader(`
+var _ Runner = (*fakeRunner)(nil)
+`,
+			))),
+		}, {
+			"Func type",
+			&Interface{Name: "Handler", Package: "design", PkgPath: "example.com/design", Func: true},
+			"fake",
+			check(expectReader(strings.NewReader(``))),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := GenerateConformance(tt.ifce, tt.pkg)
+			for _, check := range tt.checks {
+				for _, checkErr := range check(strings.NewReader(output)) {
+					if checkErr != nil {
+						t.Error(checkErr)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package mock
+
+import (
+	"go/ast"
+)
+
+// parseTypeParams adds the type parameters in tok to the ones the parser knows
+// of and returns them, with their constraints as the value types.
+func (pkg *packageParser) parseTypeParams(tok *ast.FieldList) []Value {
+	if tok == nil {
+		return nil
+	}
+	if pkg.typeParams == nil {
+		pkg.typeParams = make(map[string]struct{})
+	}
+
+	var typeParams []Value
+	for _, paramTok := range tok.List {
+		for _, idenTok := range paramTok.Names {
+			pkg.typeParams[idenTok.Name] = struct{}{}
+		}
+	}
+	for _, paramTok := range tok.List {
+		_, constraint := pkg.parseType(paramTok.Type)
+		for _, idenTok := range paramTok.Names {
+			typeParams = append(typeParams, Value{Name: idenTok.Name, Type: constraint})
+		}
+	}
+	return typeParams
+}
+
+// typeParams returns the type parameter list every generated type and
+// constructor of a generic interface is declared with, or nil when the
+// interface isn't generic.
+func (ifce Interface) typeParams() *ast.FieldList {
+	if len(ifce.TypeParams) == 0 {
+		return nil
+	}
+
+	params := fieldList()
+	for _, param := range ifce.TypeParams {
+		params.List = append(params.List, param.variable())
+	}
+	return params
+}
+
+// typeRef refers to the generated type name, instantiated with the type
+// parameters of a generic interface.
+func (ifce Interface) typeRef(name string) ast.Expr {
+	return instantiate(ast.NewIdent(name), ifce.TypeParams)
+}
+
+func (ifce Interface) fakeType() *ast.StarExpr {
+	return &ast.StarExpr{X: ifce.typeRef(ifce.fakeName())}
+}
+
+func (ifce Interface) recv() *ast.Field {
+	return field(ifce.fakeType(), "fake")
+}
+
+// methodMap returns the map type a method's per call structs are kept in.
+func (ifce Interface) methodMap(meth Method) *ast.MapType {
+	return &ast.MapType{Key: ast.NewIdent("int"), Value: ifce.typeRef(meth.structName(ifce.fakeName()))}
+}
+
+func instantiate(typ ast.Expr, params []Value) ast.Expr {
+	switch len(params) {
+	case 0:
+		return typ
+	case 1:
+		return &ast.IndexExpr{X: typ, Index: ast.NewIdent(params[0].Name)}
+	}
+
+	indices := expression()
+	for _, param := range params {
+		indices = append(indices, ast.NewIdent(param.Name))
+	}
+	return &ast.IndexListExpr{X: typ, Indices: indices}
+}
@@ -0,0 +1,156 @@
+package mock_test
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/serenize/snaker"
+
+	. "github.com/vitreuz/table-mocks/mock"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// TestGenerateGolden reads every testdata/<case>/input.go with ReadPkg and
+// compares the fake generated for each of its interfaces against
+// testdata/<case>/fake_<interface>.golden.
+func TestGenerateGolden(t *testing.T) {
+	inputs, err := filepath.Glob("testdata/*/input.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, input := range inputs {
+		dir := filepath.Dir(input)
+		name := filepath.Base(dir)
+		t.Run(name, func(t *testing.T) {
+			module := tempModule(t, "example.com/"+name, dir)
+
+			goldens, err := filepath.Glob(filepath.Join(dir, "fake_*.golden"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			stale := make(map[string]bool)
+			for _, golden := range goldens {
+				stale[golden] = true
+			}
+
+			mock, err := ReadPkg(module, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, ifce := range mock.Interfaces {
+				golden := filepath.Join(dir, "fake_"+snaker.CamelToSnake(ifce.Name)+".golden")
+				delete(stale, golden)
+
+				output := generateGolden(t, ifce)
+				if *update {
+					if err := ioutil.WriteFile(golden, output, 0644); err != nil {
+						t.Fatal(err)
+					}
+					continue
+				}
+
+				expected, err := ioutil.ReadFile(golden)
+				if err != nil {
+					t.Fatalf("missing golden file, rerun with -update: %s", err)
+				}
+				for _, checkErr := range compareBuffers(bytes.NewBuffer(expected), bytes.NewBuffer(output)) {
+					t.Errorf("%s: %s", golden, checkErr)
+				}
+			}
+
+			for golden := range stale {
+				if *update {
+					os.Remove(golden)
+					continue
+				}
+				t.Errorf("%s doesn't match any interface in %s", golden, input)
+			}
+		})
+	}
+}
+
+// TestGenerateBehaviour checks that the fakes in internal/behaviour/fake, which
+// the tests of internal/behaviour run, are exactly those generated from the
+// package's interfaces, so that those tests never run stale fakes. Rerun it
+// with -update after changing the generator.
+func TestGenerateBehaviour(t *testing.T) {
+	const dir = "internal/behaviour"
+	module := tempModule(t, repoPath+"/mock/"+dir, dir)
+
+	fakes, err := filepath.Glob(filepath.Join(dir, "fake", "*.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale := make(map[string]bool)
+	for _, fake := range fakes {
+		stale[fake] = true
+	}
+
+	mock, err := ReadPkg(module, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mock.Interfaces) == 0 {
+		t.Fatalf("expected to read interfaces from %s", dir)
+	}
+	for _, ifce := range mock.Interfaces {
+		name := filepath.Join(dir, "fake", snaker.CamelToSnake(ifce.Name)+".go")
+		delete(stale, name)
+
+		output := generateGolden(t, ifce)
+		if *update {
+			if err := ioutil.WriteFile(name, output, 0644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+
+		expected, err := ioutil.ReadFile(name)
+		if err != nil {
+			t.Fatalf("missing fake, rerun with -update: %s", err)
+		}
+		for _, checkErr := range compareBuffers(bytes.NewBuffer(expected), bytes.NewBuffer(output)) {
+			t.Errorf("%s: %s", name, checkErr)
+		}
+	}
+
+	for fake := range stale {
+		if *update {
+			os.Remove(fake)
+			continue
+		}
+		t.Errorf("%s doesn't match any interface in %s", fake, dir)
+	}
+}
+
+func generateGolden(t *testing.T, ifce Interface) []byte {
+	t.Helper()
+
+	f, err := ioutil.TempFile("", "generate_golden_")
+	if err != nil {
+		t.Fatalf("error creating tempfile: %v", err)
+	}
+	defer os.Remove(f.Name())
+
+	pkg := "fake"
+	if ifce.Local() {
+		pkg = ifce.Package
+	}
+	err = GenerateFile(&ifce, pkg, f)
+	f.Close()
+	if err != nil {
+		t.Fatalf("error generating %s: %v", ifce.Name, err)
+	}
+
+	output, err := ioutil.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return output
+}
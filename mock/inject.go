@@ -0,0 +1,161 @@
+package mock
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// panicField is the per call field holding the value a call panics with.
+const panicField = "PanicValue"
+
+func (method Method) failsName() string {
+	return toMethodName(method.Name, "Fails")
+}
+
+func (method Method) failuresName() string {
+	return toMethodName(method.Name, "Failures")
+}
+
+func (method Method) panicsName() string {
+	return toMethodName(method.Name, "Panics")
+}
+
+// injectFailures returns the statements picking the error the call fails
+// with: the one FailsOnCall set for it, or else the one picked by the end of
+// its sequence or by FailsEvery. They run once the returns and rules are
+// resolved, so that the failure is put over whatever the call returns, and
+// have to run while holding the method's lock.
+func (meth Method) injectFailures(fakeMethod ast.Expr) []ast.Stmt {
+	ret, _ := meth.errorResult()
+	fake := ast.NewIdent("fake")
+	calls := selectorExpr(fake, meth.callsName())
+	failure := ast.NewIdent("fakeFailure")
+	failed := ast.NewIdent("fakeFailed")
+
+	stmts := []ast.Stmt{&ast.AssignStmt{
+		Lhs: expression(failure, failed),
+		Tok: token.DEFINE,
+		Rhs: expression(&ast.IndexExpr{X: selectorExpr(fake, meth.failuresName()), Index: calls}),
+	}}
+	for _, fails := range []string{meth.sequenceName(), meth.failsName()} {
+		stmts = append(stmts, &ast.IfStmt{
+			Cond: &ast.UnaryExpr{Op: token.NOT, X: failed},
+			Body: blockStmt(&ast.AssignStmt{
+				Lhs: expression(failure, failed),
+				Tok: token.ASSIGN,
+				Rhs: expression(call(selectorExpr(selectorExpr(fake, fails), "Fails"), calls)),
+			}),
+		})
+	}
+
+	return append(stmts, meth.applyFailure(fakeMethod, ret))
+}
+
+// applyFailure returns the statement putting the call's failure, if it has
+// one, in its error result.
+func (meth Method) applyFailure(fakeMethod ast.Expr, ret Value) ast.Stmt {
+	return &ast.IfStmt{
+		Cond: ast.NewIdent("fakeFailed"),
+		Body: blockStmt(assign(selectorExpr(fakeMethod, ret.fieldName()), ast.NewIdent("fakeFailure"))),
+	}
+}
+
+// takePanic returns the statement giving the call the value PanicsOnCall set
+// for it. It has to run while holding the method's lock.
+func (meth Method) takePanic(fakeMethod ast.Expr) ast.Stmt {
+	fake := ast.NewIdent("fake")
+	value := ast.NewIdent("value")
+
+	return &ast.IfStmt{
+		Init: &ast.AssignStmt{
+			Lhs: expression(value, ast.NewIdent("ok")),
+			Tok: token.DEFINE,
+			Rhs: expression(&ast.IndexExpr{X: selectorExpr(fake, meth.panicsName()), Index: selectorExpr(fake, meth.callsName())}),
+		},
+		Cond: ast.NewIdent("ok"),
+		Body: blockStmt(assign(selectorExpr(fakeMethod, panicField), value)),
+	}
+}
+
+// injectPanic returns the statement panicking with the value programmed by
+// PanicsOnCall. Like unexpected, it has to run after the lock is released.
+func (meth Method) injectPanic(fakeMethod ast.Expr) ast.Stmt {
+	value := selectorExpr(fakeMethod, panicField)
+
+	return &ast.IfStmt{
+		Cond: &ast.BinaryExpr{X: value, Op: token.NEQ, Y: ast.NewIdent("nil")},
+		Body: blockStmt(exprStmt(call(ast.NewIdent("panic"), value))),
+	}
+}
+
+// unprogrammed returns the condition under which a call got nothing
+// programmed: no returns, no rule and, for methods with a trailing error
+// result, no failure.
+func (meth Method) unprogrammed(configured ast.Expr) ast.Expr {
+	cond := ast.Expr(&ast.UnaryExpr{Op: token.NOT, X: configured})
+	if _, ok := meth.errorResult(); ok {
+		cond = &ast.BinaryExpr{X: cond, Op: token.LAND, Y: &ast.UnaryExpr{Op: token.NOT, X: ast.NewIdent("fakeFailed")}}
+	}
+	return cond
+}
+
+// generateInjections returns RunPanicsOnCall, and for methods with a trailing
+// error result RunFailsOnCall and RunFailsEvery.
+func (meth Method) generateInjections(ifce Interface) []ast.Decl {
+	decls := []ast.Decl{meth.generateOnCall(ifce, "PanicsOnCall", field(ast.NewIdent("interface{}"), "value"), meth.panicsName())}
+	if ret, ok := meth.errorResult(); ok {
+		decls = append(decls,
+			meth.generateOnCall(ifce, "FailsOnCall", field(ret.Type, ret.argName()), meth.failuresName()),
+			meth.generateFailsEvery(ifce),
+		)
+	}
+
+	return decls
+}
+
+// generateOnCall returns a method adding what param holds for the call to
+// the method's per call values in the field, such as its tablemock.Delays,
+// leaving the returns programmed for the call alone.
+func (meth Method) generateOnCall(ifce Interface, suffix string, param *ast.Field, fieldName string) *ast.FuncDecl {
+	fake := ast.NewIdent("fake")
+	fakeMethodMutex := selectorExpr(fake, meth.mutexName())
+	values := selectorExpr(fake, fieldName)
+
+	body := blockStmt(
+		exprStmt(call(selectorExpr(fakeMethodMutex, "Lock"))),
+		assign(values, call(selectorExpr(values, "With"), ast.NewIdent("call"), param.Names[0])),
+		exprStmt(call(selectorExpr(fakeMethodMutex, "Unlock"))),
+		&ast.ReturnStmt{Results: expression(fake)},
+	)
+
+	recv := ifce.recv()
+	params := fieldList(field(ast.NewIdent("int"), "call"), param)
+	results := fieldList(field(ifce.fakeType()))
+
+	return funcDecl(recv, strings.Title(meth.Name)+suffix, params, results, body)
+}
+func (meth Method) generateFailsEvery(ifce Interface) *ast.FuncDecl {
+	ret, _ := meth.errorResult()
+	fake := ast.NewIdent("fake")
+	fakeMethodMutex := selectorExpr(fake, meth.mutexName())
+
+	body := blockStmt(
+		exprStmt(call(selectorExpr(fakeMethodMutex, "Lock"))),
+		assign(selectorExpr(fake, meth.failsName()), &ast.CompositeLit{
+			Type: selectorExpr(ast.NewIdent("tablemock"), "Every"),
+			Elts: expression(
+				&ast.KeyValueExpr{Key: ast.NewIdent("K"), Value: ast.NewIdent("k")},
+				&ast.KeyValueExpr{Key: ast.NewIdent("Err"), Value: ast.NewIdent(ret.argName())},
+			),
+		}),
+		exprStmt(call(selectorExpr(fakeMethodMutex, "Unlock"))),
+		&ast.ReturnStmt{Results: expression(fake)},
+	)
+
+	recv := ifce.recv()
+	params := fieldList(field(ast.NewIdent("int"), "k"), field(ret.Type, ret.argName()))
+	results := fieldList(field(ifce.fakeType()))
+
+	return funcDecl(recv, strings.Title(meth.Name)+"FailsEvery", params, results, body)
+}
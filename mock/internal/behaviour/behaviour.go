@@ -0,0 +1,19 @@
+// Package behaviour declares the interfaces whose fakes, generated into
+// package fake, are run by the tests of this package to check how the
+// features of a generated fake work together. TestGenerateBehaviour of package
+// mock fails while the fakes differ from what the generator makes of these
+// interfaces; regenerate them with
+//
+//	go test ./mock -run TestGenerateBehaviour -update
+package behaviour
+
+import "context"
+
+type Store interface {
+	Get(key string) (string, error)
+	Load(key string, value *string) error
+}
+
+type Fetcher interface {
+	Fetch(ctx context.Context, url string) ([]byte, error)
+}
@@ -0,0 +1,52 @@
+package behaviour_test
+
+import (
+	"errors"
+
+	"github.com/vitreuz/table-mocks/mock/internal/behaviour"
+)
+
+var (
+	boom        = errors.New("boom")
+	errNotFound = errors.New("not found")
+)
+
+// realStore is the Store spies forward to.
+type realStore map[string]string
+
+func (s realStore) Get(key string) (string, error) {
+	value, ok := s[key]
+	if !ok {
+		return "", errNotFound
+	}
+	return value, nil
+}
+
+func (s realStore) Load(key string, value *string) error {
+	got, err := s.Get(key)
+	if err != nil {
+		return err
+	}
+	*value = got
+	return nil
+}
+
+var stored = realStore{"a": "A", "b": "B", "c": "C", "d": "D"}
+
+var keys = []string{"a", "b", "c", "d"}
+
+type result struct {
+	value string
+	err   error
+}
+
+// getAll calls store.Get once per key, in order, and returns what each call
+// returned.
+func getAll(store behaviour.Store, keys ...string) []result {
+	var results []result
+	for _, key := range keys {
+		value, err := store.Get(key)
+		results = append(results, result{value, err})
+	}
+	return results
+}
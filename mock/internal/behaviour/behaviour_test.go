@@ -0,0 +1,345 @@
+package behaviour_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/vitreuz/table-mocks/internal/tbtest"
+	"github.com/vitreuz/table-mocks/match"
+	"github.com/vitreuz/table-mocks/mock/internal/behaviour/fake"
+	"github.com/vitreuz/table-mocks/tablemock"
+)
+
+func TestStoreGet(t *testing.T) {
+	p1 := fake.StoreGetMethod{StringResult: "p1"}
+	p2 := fake.StoreGetMethod{StringResult: "p2"}
+	q1 := fake.StoreGetMethod{StringResult: "q1"}
+
+	tests := [...]struct {
+		name    string
+		program func(*fake.Store)
+		expect  []result
+	}{
+		{
+			"Returns",
+			func(f *fake.Store) { f.GetReturns("x", nil) },
+			[]result{{"x", nil}, {"", nil}},
+		}, {
+			"ForCall",
+			func(f *fake.Store) {
+				f.GetForCall(1, func(m fake.StoreGetMethod) fake.StoreGetMethod {
+					m.StringResult = "y"
+					return m
+				})
+			},
+			[]result{{"", nil}, {"y", nil}},
+		}, {
+			"Sequence repeating its last returns",
+			func(f *fake.Store) { f.GetReturnsSequence(p1, p2) },
+			[]result{{"p1", nil}, {"p2", nil}, {"p2", nil}},
+		}, {
+			"Sequence replaced by a shorter one",
+			func(f *fake.Store) {
+				f.GetReturnsSequence(p1, p2, fake.StoreGetMethod{StringResult: "p3"}).GetReturnsSequence(q1)
+			},
+			[]result{{"q1", nil}, {"q1", nil}, {"q1", nil}, {"q1", nil}},
+		}, {
+			"Sequence with a step changed by ForCall",
+			func(f *fake.Store) {
+				f.GetReturnsSequence(p1, p2).GetForCall(1, func(m fake.StoreGetMethod) fake.StoreGetMethod {
+					m.ErrResult = boom
+					return m
+				})
+			},
+			[]result{{"p1", nil}, {"p2", boom}, {"p2", boom}},
+		}, {
+			"Sequence cycling",
+			func(f *fake.Store) { f.GetReturnsSequence(p1, p2).GetSequenceEnd(tablemock.Cycle) },
+			[]result{{"p1", nil}, {"p2", nil}, {"p1", nil}},
+		}, {
+			"Sequence failing after its end",
+			func(f *fake.Store) { f.GetReturnsSequence(p1, p2).GetSequenceEnd(tablemock.FailAfter) },
+			[]result{{"p1", nil}, {"p2", nil}, {"", tablemock.ErrSequenceEnded}},
+		}, {
+			"When",
+			func(f *fake.Store) { f.GetWhen(match.Eq("b")).Returns("w", nil) },
+			[]result{{"", nil}, {"w", nil}, {"", nil}},
+		}, {
+			"When over Returns",
+			func(f *fake.Store) { f.GetReturns("x", nil).GetWhen(match.Any()).Returns("w", nil) },
+			[]result{{"w", nil}, {"w", nil}},
+		}, {
+			"FailsEvery",
+			func(f *fake.Store) { f.GetFailsEvery(2, boom) },
+			[]result{{"", nil}, {"", boom}, {"", nil}, {"", boom}},
+		}, {
+			"FailsEvery over When",
+			func(f *fake.Store) { f.GetWhen(match.Any()).Returns("w", nil).GetFailsEvery(2, boom) },
+			[]result{{"w", nil}, {"w", boom}},
+		}, {
+			"FailsOnCall",
+			func(f *fake.Store) { f.GetFailsOnCall(1, boom) },
+			[]result{{"", nil}, {"", boom}, {"", nil}},
+		}, {
+			"FailsOnCall over When",
+			func(f *fake.Store) { f.GetWhen(match.Any()).Returns("w", nil).GetFailsOnCall(1, boom) },
+			[]result{{"w", nil}, {"w", boom}, {"w", nil}},
+		}, {
+			"Sequence with a failed call",
+			func(f *fake.Store) { f.GetReturnsSequence(p1).GetFailsOnCall(1, boom) },
+			[]result{{"p1", nil}, {"p1", boom}, {"p1", nil}},
+		}, {
+			"Sequence with a delayed call",
+			func(f *fake.Store) { f.GetReturnsSequence(p1, p2).GetDelayOnCall(3, time.Millisecond) },
+			[]result{{"p1", nil}, {"p2", nil}, {"p2", nil}, {"p2", nil}},
+		}, {
+			"Returns with a delay",
+			func(f *fake.Store) { f.GetReturns("x", nil).GetDelay(time.Millisecond) },
+			[]result{{"x", nil}, {"", nil}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := fake.NewStore()
+			tt.program(store)
+
+			if got := getAll(store, keys[:len(tt.expect)]...); !reflect.DeepEqual(got, tt.expect) {
+				t.Errorf("expected %v but got %v", tt.expect, got)
+			}
+		})
+	}
+}
+
+func TestStoreSpy(t *testing.T) {
+	tests := [...]struct {
+		name    string
+		program func(*fake.Store)
+		expect  []result
+	}{
+		{
+			"Forwarding",
+			func(f *fake.Store) {},
+			[]result{{"A", nil}, {"B", nil}},
+		}, {
+			"Returns",
+			func(f *fake.Store) { f.GetReturns("x", nil) },
+			[]result{{"x", nil}, {"B", nil}},
+		}, {
+			"When",
+			func(f *fake.Store) { f.GetWhen(match.Eq("b")).Returns("w", nil) },
+			[]result{{"A", nil}, {"w", nil}, {"C", nil}},
+		}, {
+			"Sequence",
+			func(f *fake.Store) { f.GetReturnsSequence(fake.StoreGetMethod{StringResult: "p1"}) },
+			[]result{{"p1", nil}, {"p1", nil}},
+		}, {
+			"DelayOnCall",
+			func(f *fake.Store) { f.GetDelayOnCall(0, time.Millisecond) },
+			[]result{{"A", nil}, {"B", nil}},
+		}, {
+			"FailsOnCall",
+			func(f *fake.Store) { f.GetFailsOnCall(1, boom) },
+			[]result{{"A", nil}, {"B", boom}, {"C", nil}},
+		}, {
+			"FailsEvery",
+			func(f *fake.Store) { f.GetFailsEvery(2, boom) },
+			[]result{{"A", nil}, {"B", boom}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := fake.NewStoreSpy(stored)
+			tt.program(store)
+
+			if got := getAll(store, keys[:len(tt.expect)]...); !reflect.DeepEqual(got, tt.expect) {
+				t.Errorf("expected %v but got %v", tt.expect, got)
+			}
+		})
+	}
+}
+
+func TestStoreStrict(t *testing.T) {
+	tests := [...]struct {
+		name    string
+		program func(*fake.Store)
+		calls   int
+		fatals  []string
+	}{
+		{
+			"Returns",
+			func(f *fake.Store) { f.GetReturns("x", nil) },
+			1,
+			nil,
+		}, {
+			"Nothing programmed",
+			func(f *fake.Store) {},
+			1,
+			[]string{`unexpected call Store.Get("a") on call 0`},
+		}, {
+			"Past Returns",
+			func(f *fake.Store) { f.GetReturns("x", nil) },
+			2,
+			[]string{`unexpected call Store.Get("b") on call 1`},
+		}, {
+			"Sequence",
+			func(f *fake.Store) { f.GetReturnsSequence(fake.StoreGetMethod{StringResult: "p1"}) },
+			3,
+			nil,
+		}, {
+			"When",
+			func(f *fake.Store) { f.GetWhen(match.Any()).Returns("w", nil) },
+			2,
+			nil,
+		}, {
+			"DelayOnCall",
+			func(f *fake.Store) { f.GetDelayOnCall(0, time.Millisecond) },
+			1,
+			[]string{`unexpected call Store.Get("a") on call 0`},
+		}, {
+			"FailsOnCall",
+			func(f *fake.Store) { f.GetFailsOnCall(0, boom) },
+			1,
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tb := tbtest.New(t)
+			tb.Run(func(tb testing.TB) {
+				store := fake.NewStore(tablemock.Strict(tb))
+				tt.program(store)
+				getAll(store, keys[:tt.calls]...)
+			})
+
+			if !reflect.DeepEqual(tb.Fatals, tt.fatals) {
+				t.Errorf("expected fatals %q but got %q", tt.fatals, tb.Fatals)
+			}
+		})
+	}
+}
+
+func TestStorePanicsOnCall(t *testing.T) {
+	store := fake.NewStore().GetReturnsSequence(fake.StoreGetMethod{StringResult: "p1"}).GetPanicsOnCall(1, "p")
+
+	expect := []interface{}{nil, "p", nil}
+	for i, key := range keys[:len(expect)] {
+		func() {
+			defer func() {
+				if r := recover(); r != expect[i] {
+					t.Errorf("expected call %d to panic with %v but got %v", i, expect[i], r)
+				}
+			}()
+			if value, err := store.Get(key); value != "p1" || err != nil {
+				t.Errorf("expected call %d to return p1 <nil> but got %q %v", i, value, err)
+			}
+		}()
+	}
+}
+
+func TestStoreLoadSets(t *testing.T) {
+	store := fake.NewStore().LoadSetsValue("v").LoadFailsOnCall(1, boom)
+
+	var value string
+	if err := store.Load("a", &value); value != "v" || err != nil {
+		t.Errorf("expected v <nil> but got %q %v", value, err)
+	}
+	value = ""
+	if err := store.Load("b", &value); value != "v" || err != boom {
+		t.Errorf("expected v boom but got %q %v", value, err)
+	}
+}
+
+func TestFetcherBlock(t *testing.T) {
+	fetcher := fake.NewFetcher().FetchReturns([]byte("x"), nil).FetchBlock()
+
+	done := make(chan result)
+	go func() {
+		got, err := fetcher.Fetch(context.Background(), "url")
+		done <- result{string(got), err}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := fetcher.FetchWaitForCalls(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case got := <-done:
+		t.Fatalf("expected the call to block but it returned %v", got)
+	default:
+	}
+
+	fetcher.FetchRelease()
+	if got := <-done; !reflect.DeepEqual(got, result{"x", nil}) {
+		t.Errorf("expected x <nil> but got %v", got)
+	}
+}
+
+func TestFetcherContext(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := [...]struct {
+		name    string
+		opts    []tablemock.Option
+		program func(*fake.Fetcher)
+		ctx     context.Context
+		expect  result
+	}{
+		{
+			"Done context",
+			nil,
+			func(f *fake.Fetcher) {},
+			cancelled,
+			result{"x", nil},
+		}, {
+			"HonorContext with a done context",
+			[]tablemock.Option{tablemock.HonorContext()},
+			func(f *fake.Fetcher) {},
+			cancelled,
+			result{"", context.Canceled},
+		}, {
+			"Block with a context timing out",
+			nil,
+			func(f *fake.Fetcher) { f.FetchBlock() },
+			nil,
+			result{"", context.DeadlineExceeded},
+		}, {
+			"Delay with a context timing out",
+			nil,
+			func(f *fake.Fetcher) { f.FetchDelay(time.Hour) },
+			nil,
+			result{"", context.DeadlineExceeded},
+		}, {
+			"DelayOnCall with a context timing out",
+			nil,
+			func(f *fake.Fetcher) { f.FetchDelayOnCall(0, time.Hour) },
+			nil,
+			result{"", context.DeadlineExceeded},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetcher := fake.NewFetcher(tt.opts...).FetchReturns([]byte("x"), nil)
+			tt.program(fetcher)
+			defer fetcher.FetchRelease()
+
+			ctx := tt.ctx
+			if ctx == nil {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+				defer cancel()
+			}
+			got, err := fetcher.Fetch(ctx, "url")
+			if result := (result{string(got), err}); !reflect.DeepEqual(result, tt.expect) {
+				t.Errorf("expected %v but got %v", tt.expect, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package mock
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// blankLine marks the first token of a struct field or statement that the
+// builder wants set apart from the one before it. The layout replaces it with a
+// real position; printed without a layout it resolves to no line at all.
+const blankLine token.Pos = 1
+
+// spaced returns a copy of the field or statement that starts with blankLine.
+func spaced(node ast.Node) ast.Node {
+	return withPos(node, blankLine)
+}
+
+// layout positions the nodes of a generated file for the printer. The builder
+// creates nodes without positions, which go/printer prints without any empty
+// lines. The printer only keeps count of lines between tokens that have a
+// position, so layout gives one to every declaration, struct field and top
+// level statement, each on the line after the one before. It skips a line
+// between declarations and wherever the builder used spaced.
+//
+// Every line of the laid out file is a single byte long, so line n starts at
+// token.Pos(n).
+type layout struct {
+	line int
+}
+
+// next moves l to the next line and returns its position.
+func (l *layout) next() token.Pos {
+	l.line++
+	return token.Pos(l.line)
+}
+
+// fileSet returns the file set the laid out nodes have to be printed with.
+func (l *layout) fileSet() *token.FileSet {
+	fset := token.NewFileSet()
+	file := fset.AddFile("", 1, l.line+1)
+
+	lines := make([]int, l.line+1)
+	for i := range lines {
+		lines[i] = i
+	}
+	file.SetLines(lines)
+
+	return fset
+}
+
+func (l *layout) file(node *ast.File) {
+	for _, group := range node.Comments {
+		for _, comment := range group.List {
+			comment.Slash = l.next()
+		}
+		l.line++
+	}
+
+	node.Package = l.next()
+	l.decls(node.Decls)
+}
+
+// decls lays out a list of declarations, each after an empty line.
+func (l *layout) decls(decls []ast.Decl) {
+	for i, decl := range decls {
+		if l.line > 0 {
+			l.line++
+		}
+		decls[i] = l.decl(decl)
+	}
+}
+
+func (l *layout) decl(decl ast.Decl) ast.Decl {
+	switch decl := decl.(type) {
+	case *ast.FuncDecl:
+		fn := *decl
+		fn.Type = withPos(decl.Type, l.next()).(*ast.FuncType)
+		if fn.Body != nil {
+			fn.Body = l.block(fn.Body)
+		}
+		return &fn
+	case *ast.GenDecl:
+		gen := *decl
+		gen.TokPos = l.next()
+		if gen.Lparen.IsValid() || gen.Tok == token.IMPORT {
+			gen.Lparen = gen.TokPos
+		}
+		if spec, ok := singleStruct(&gen); ok {
+			spec.Type = &ast.StructType{Fields: l.fields(spec.Type.(*ast.StructType).Fields)}
+			gen.Specs = []ast.Spec{spec}
+		}
+		return &gen
+	}
+
+	panic("cannot lay out declaration")
+}
+
+// singleStruct returns a copy of the spec of a declaration of a single struct
+// type, whose fields are then laid out one by one.
+func singleStruct(gen *ast.GenDecl) (*ast.TypeSpec, bool) {
+	if gen.Tok != token.TYPE || len(gen.Specs) != 1 {
+		return nil, false
+	}
+	spec, ok := gen.Specs[0].(*ast.TypeSpec)
+	if !ok {
+		return nil, false
+	}
+	if _, ok := spec.Type.(*ast.StructType); !ok {
+		return nil, false
+	}
+
+	cp := *spec
+	return &cp, true
+}
+
+func (l *layout) fields(list *ast.FieldList) *ast.FieldList {
+	laid := &ast.FieldList{}
+	for i, f := range list.List {
+		l.spacing(f, i)
+		laid.List = append(laid.List, withPos(f, l.next()).(*ast.Field))
+	}
+	return laid
+}
+
+// block lays out the statements of a body. The braces are placed on lines of
+// their own so that the printer never joins a short body onto one line.
+func (l *layout) block(block *ast.BlockStmt) *ast.BlockStmt {
+	laid := &ast.BlockStmt{Lbrace: token.Pos(l.line)}
+	for i, stmt := range block.List {
+		l.spacing(stmt, i)
+		pos := l.next()
+		ellipses(stmt, pos)
+		laid.List = append(laid.List, withPos(stmt, pos).(ast.Stmt))
+	}
+	laid.Rbrace = l.next()
+	return laid
+}
+
+// ellipses moves the ... of the variadic calls in a statement onto the line
+// the statement starts on. The builder has to give them some position for the
+// printer to print them at all.
+func ellipses(stmt ast.Stmt, pos token.Pos) {
+	ast.Inspect(stmt, func(node ast.Node) bool {
+		if call, ok := node.(*ast.CallExpr); ok && call.Ellipsis.IsValid() {
+			call.Ellipsis = pos
+		}
+		return true
+	})
+}
+
+// spacing leaves an empty line before a node that asked for one, unless it is
+// the first in its list.
+func (l *layout) spacing(node ast.Node, i int) {
+	if i > 0 && node.Pos() == blankLine {
+		l.line++
+	}
+}
+
+// withPos returns a copy of node with the position of its first token set to
+// pos. Only the nodes on the way to that token are copied, which keeps any
+// nodes the builder shares between statements untouched.
+func withPos(node ast.Node, pos token.Pos) ast.Node {
+	switch n := node.(type) {
+	case *ast.Field:
+		cp := *n
+		if len(n.Names) > 0 {
+			cp.Names = append([]*ast.Ident{withPos(n.Names[0], pos).(*ast.Ident)}, n.Names[1:]...)
+		} else {
+			cp.Type = withPos(n.Type, pos).(ast.Expr)
+		}
+		return &cp
+	case *ast.FuncType:
+		cp := *n
+		cp.Func = pos
+		return &cp
+
+	case *ast.ExprStmt:
+		cp := *n
+		cp.X = withPos(n.X, pos).(ast.Expr)
+		return &cp
+	case *ast.AssignStmt:
+		cp := *n
+		cp.Lhs = append([]ast.Expr{withPos(n.Lhs[0], pos).(ast.Expr)}, n.Lhs[1:]...)
+		return &cp
+	case *ast.IncDecStmt:
+		cp := *n
+		cp.X = withPos(n.X, pos).(ast.Expr)
+		return &cp
+	case *ast.ReturnStmt:
+		cp := *n
+		cp.Return = pos
+		return &cp
+	case *ast.IfStmt:
+		cp := *n
+		cp.If = pos
+		return &cp
+	case *ast.RangeStmt:
+		cp := *n
+		cp.For = pos
+		return &cp
+	case *ast.ForStmt:
+		cp := *n
+		cp.For = pos
+		return &cp
+	case *ast.SelectStmt:
+		cp := *n
+		cp.Select = pos
+		return &cp
+	case *ast.SwitchStmt:
+		cp := *n
+		cp.Switch = pos
+		return &cp
+	case *ast.DeferStmt:
+		cp := *n
+		cp.Defer = pos
+		return &cp
+	case *ast.GoStmt:
+		cp := *n
+		cp.Go = pos
+		return &cp
+	case *ast.BranchStmt:
+		cp := *n
+		cp.TokPos = pos
+		return &cp
+	case *ast.DeclStmt:
+		gen := *n.Decl.(*ast.GenDecl)
+		gen.TokPos = pos
+		return &ast.DeclStmt{Decl: &gen}
+	case *ast.SendStmt:
+		cp := *n
+		cp.Chan = withPos(n.Chan, pos).(ast.Expr)
+		return &cp
+
+	case *ast.Ident:
+		cp := *n
+		cp.NamePos = pos
+		return &cp
+	case *ast.SelectorExpr:
+		cp := *n
+		cp.X = withPos(n.X, pos).(ast.Expr)
+		return &cp
+	case *ast.CallExpr:
+		cp := *n
+		cp.Fun = withPos(n.Fun, pos).(ast.Expr)
+		return &cp
+	case *ast.IndexExpr:
+		cp := *n
+		cp.X = withPos(n.X, pos).(ast.Expr)
+		return &cp
+	case *ast.IndexListExpr:
+		cp := *n
+		cp.X = withPos(n.X, pos).(ast.Expr)
+		return &cp
+	case *ast.StarExpr:
+		cp := *n
+		cp.Star = pos
+		return &cp
+	case *ast.UnaryExpr:
+		cp := *n
+		cp.OpPos = pos
+		return &cp
+	case *ast.ParenExpr:
+		cp := *n
+		cp.Lparen = pos
+		return &cp
+	case *ast.BasicLit:
+		cp := *n
+		cp.ValuePos = pos
+		return &cp
+	}
+
+	panic("cannot position node")
+}
@@ -0,0 +1,135 @@
+package mock
+
+import (
+	"fmt"
+	"go/ast"
+	"strings"
+)
+
+// Local reports whether the fake for ifce has to be generated into the package
+// the interface was read from, since the interface or some of the types it
+// refers to are unexported.
+func (ifce Interface) Local() bool {
+	return ifce.Package != "" && (!ast.IsExported(ifce.Name) || len(ifce.Unexported) > 0)
+}
+
+// fakeName is the name of the fake type, which the other generated types are
+// named after. A fake generated into the package of its interface is
+// unexported, so that it neither clashes with the interface nor adds to the
+// package's API.
+func (ifce Interface) fakeName() string {
+	if ifce.inPkg {
+		return "fake" + strings.Title(ifce.Name)
+	}
+	return strings.Title(ifce.Name)
+}
+
+// constructorName is the name of the func creating the fake, like NewRunner,
+// or newFakeRunner for an unexported fake.
+func (ifce Interface) constructorName() string {
+	if ifce.inPkg {
+		return "new" + strings.Title(ifce.fakeName())
+	}
+	return "New" + ifce.fakeName()
+}
+
+// inPackage returns ifce as seen from pkg. A fake generated into the package
+// the interface was read from is unexported, refers to the package's types
+// without their qualifier and doesn't import the package itself.
+func (ifce Interface) inPackage(pkg string) Interface {
+	if !ifce.samePackage(pkg) {
+		return ifce
+	}
+
+	local := ifce
+	local.inPkg = true
+	local.Imports = nil
+	for _, imp := range ifce.Imports {
+		if imp != ifce.PkgPath {
+			local.Imports = append(local.Imports, imp)
+		}
+	}
+	local.TypeParams = unqualifyValues(ifce.TypeParams, ifce.Package)
+	local.Methods = nil
+	for _, meth := range ifce.Methods {
+		meth.Args = unqualifyValues(meth.Args, ifce.Package)
+		meth.Rets = unqualifyValues(meth.Rets, ifce.Package)
+		local.Methods = append(local.Methods, meth)
+	}
+	return local
+}
+
+// checkPackage returns an error when the fake for ifce can't be generated into
+// pkg because the interface, or types it uses, are unexported in another
+// package.
+func (ifce Interface) checkPackage(pkg string) error {
+	switch {
+	case !ifce.Local() || ifce.samePackage(pkg):
+		return nil
+	case !ast.IsExported(ifce.Name):
+		return fmt.Errorf("%s is unexported, so its fake can only be generated into package %s", ifce.Name, ifce.Package)
+	}
+	return fmt.Errorf("the fake for %s uses the unexported types %s of package %s, so it can only be generated into that package",
+		ifce.Name, strings.Join(ifce.Unexported, ", "), ifce.Package)
+}
+
+func unqualifyValues(vals []Value, pkg string) []Value {
+	var local []Value
+	for _, val := range vals {
+		local = append(local, Value{Name: val.Name, Type: unqualify(val.Type, pkg)})
+	}
+	return local
+}
+
+// unqualify returns a copy of the type typ, as parsed by parseType, with the
+// types of package pkg referred to by their names alone.
+func unqualify(typ ast.Expr, pkg string) ast.Expr {
+	switch typ := typ.(type) {
+	case *ast.SelectorExpr:
+		if x, ok := typ.X.(*ast.Ident); ok && x.Name == pkg {
+			return ast.NewIdent(typ.Sel.Name)
+		}
+		return typ
+	case *ast.Ellipsis:
+		return &ast.Ellipsis{Elt: unqualify(typ.Elt, pkg)}
+	case *ast.ArrayType:
+		return &ast.ArrayType{Len: typ.Len, Elt: unqualify(typ.Elt, pkg)}
+	case *ast.StarExpr:
+		return &ast.StarExpr{X: unqualify(typ.X, pkg)}
+	case *ast.MapType:
+		return &ast.MapType{Key: unqualify(typ.Key, pkg), Value: unqualify(typ.Value, pkg)}
+	case *ast.ChanType:
+		return &ast.ChanType{Dir: typ.Dir, Value: unqualify(typ.Value, pkg)}
+	case *ast.FuncType:
+		return &ast.FuncType{Params: unqualifyFields(typ.Params, pkg), Results: unqualifyFields(typ.Results, pkg)}
+	case *ast.InterfaceType:
+		return &ast.InterfaceType{Methods: unqualifyFields(typ.Methods, pkg)}
+	case *ast.StructType:
+		return &ast.StructType{Fields: unqualifyFields(typ.Fields, pkg)}
+	case *ast.IndexExpr:
+		return &ast.IndexExpr{X: unqualify(typ.X, pkg), Index: unqualify(typ.Index, pkg)}
+	case *ast.IndexListExpr:
+		indices := expression()
+		for _, index := range typ.Indices {
+			indices = append(indices, unqualify(index, pkg))
+		}
+		return &ast.IndexListExpr{X: unqualify(typ.X, pkg), Indices: indices}
+	case *ast.UnaryExpr:
+		return &ast.UnaryExpr{Op: typ.Op, X: unqualify(typ.X, pkg)}
+	case *ast.BinaryExpr:
+		return &ast.BinaryExpr{X: unqualify(typ.X, pkg), Op: typ.Op, Y: unqualify(typ.Y, pkg)}
+	}
+	return typ
+}
+
+func unqualifyFields(list *ast.FieldList, pkg string) *ast.FieldList {
+	if list == nil {
+		return nil
+	}
+
+	local := fieldList()
+	for _, f := range list.List {
+		local.List = append(local.List, &ast.Field{Names: f.Names, Type: unqualify(f.Type, pkg)})
+	}
+	return local
+}
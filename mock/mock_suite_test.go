@@ -34,6 +34,16 @@ func (t testInterface) WithImport(imp string) testInterface {
 	return t
 }
 
+func (t testInterface) WithSource(pkg, pkgPath string) testInterface {
+	t.Package, t.PkgPath = pkg, pkgPath
+	return t
+}
+
+func (t testInterface) asFunc() testInterface {
+	t.Func = true
+	return t
+}
+
 func (t testInterface) ToInterface() *Interface { return &t.Interface }
 
 // METHOD
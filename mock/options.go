@@ -0,0 +1,53 @@
+package mock
+
+import "go/build"
+
+// ReadOption configures how ReadPkg reads a package.
+type ReadOption func(*readConfig)
+
+type readConfig struct {
+	expandAliases bool
+	build         build.Context
+}
+
+func newReadConfig(opts ...ReadOption) readConfig {
+	config := readConfig{build: build.Default}
+	config.build.BuildTags = append([]string(nil), build.Default.BuildTags...)
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return config
+}
+
+// ExpandAliases makes the fakes refer to the target types of the aliases a
+// package declares, such as string for `type ID = string`, instead of to the
+// aliases themselves.
+func ExpandAliases() ReadOption {
+	return func(c *readConfig) {
+		c.expandAliases = true
+	}
+}
+
+// BuildTags adds tags to those satisfied when the build constraints of the
+// package's files are evaluated.
+func BuildTags(tags ...string) ReadOption {
+	return func(c *readConfig) {
+		c.build.BuildTags = append(c.build.BuildTags, tags...)
+	}
+}
+
+// GOOS reads the files of the package built for goos, instead of for the
+// operating system the generator runs on.
+func GOOS(goos string) ReadOption {
+	return func(c *readConfig) {
+		c.build.GOOS = goos
+	}
+}
+
+// GOARCH reads the files of the package built for goarch, instead of for the
+// architecture the generator runs on.
+func GOARCH(goarch string) ReadOption {
+	return func(c *readConfig) {
+		c.build.GOARCH = goarch
+	}
+}
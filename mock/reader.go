@@ -6,8 +6,11 @@ import (
 	"go/build"
 	"go/parser"
 	"go/token"
+	"go/types"
 	"io"
+	"io/ioutil"
 	"os"
+	"path"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -21,11 +24,34 @@ type Mock struct {
 	Interfaces []Interface
 }
 
-// Interface represents a single instance of an interface.
+// Interface represents a single instance of an interface. Package and PkgPath
+// name the package the interface was read from; they are empty when the source
+// package is unknown. TypeParams holds the type parameters of a generic
+// interface, with their constraints as the value types. Func marks a named
+// func type, read as an interface whose only method is Call. Unexported lists
+// the unexported types of the source package the interface refers to, which
+// only a fake generated into that package can use.
 type Interface struct {
-	Name    string
-	Imports []string
-	Methods []Method
+	Name       string
+	Package    string
+	PkgPath    string
+	TypeParams []Value
+	Imports    []string
+	Methods    []Method
+	Func       bool
+	Unexported []string
+
+	// typeTerms is where the interface, or one it embeds, first has a type
+	// term such as ~int, if it has any.
+	typeTerms token.Pos
+	// siblings are the names of the other interfaces read along with the
+	// interface whose fakes are generated into the same package.
+	siblings []string
+	// foreign is the first interface the interface, or one it embeds, embeds
+	// but can't be read, such as io.Reader, if it has any.
+	foreign *ast.Field
+	// inPkg marks the interface as seen from its own package by inPackage.
+	inPkg bool
 }
 
 // Method represents a single interface method with all of its args and return
@@ -34,20 +60,35 @@ type Method struct {
 	Name string
 	Args []Value
 	Rets []Value
+
+	// pos is where the method is declared, to report conflicts with.
+	pos token.Pos
 }
 
 // Value represents an arg or return value.
 type Value struct {
 	Name string
 	Type ast.Expr
+
+	// local names the value in a generated body instead of Name when Name
+	// clashes with the names the body uses, as set by withLocalNames.
+	local string
 }
 
 var fset *token.FileSet
 
 type packageParser struct {
-	pkg     *ast.Ident
-	imports map[string]struct{}
-	scope   map[string]*ast.Object
+	pkg        *ast.Ident
+	imports    map[string]struct{}
+	scope      map[string]*ast.Object
+	typeParams map[string]struct{}
+	unexported map[string]struct{}
+
+	expandAliases bool
+
+	// embedding holds the interfaces being parsed, outermost first, to catch
+	// embedding cycles.
+	embedding []*ast.TypeSpec
 
 	selfImport bool
 }
@@ -58,120 +99,302 @@ func NewPackageParser(pkg *ast.Ident) *packageParser {
 
 type fileReader struct{}
 
-func ReadPkg(dir string, selects []string) *Mock {
-	gopath := os.Getenv("GOPATH")
-	if gopath == "" {
-		gopath = build.Default.GOPATH
-	}
+// ReadPkg reads the interfaces of the package in dir, or only those named in
+// selects if any. It returns an error for an interface that can't be faked.
+func ReadPkg(dir string, selects []string, opts ...ReadOption) (*Mock, error) {
+	gopath := gopathDir()
+	config := newReadConfig(opts...)
 
 	logrus.WithFields(logrus.Fields{
 		"gopath": gopath,
 		"dir":    dir,
 	}).Println("reading dir")
 
+	mock := new(Mock)
+	pkg, pkgPath, files, err := parseDir(dir, gopath, config.build)
+	if err != nil {
+		return nil, err
+	}
+	if pkg == nil {
+		return mock, nil
+	}
+
+	pkgName := pkg.Name
+	mock.Package = pkgName
+	logrus.WithFields(logrus.Fields{
+		"pkg_name":   pkgName,
+		"file_count": len(pkg.Files),
+	}).Println("parsing package")
+
+	// Aliases may be expanded to types using the imports of another file, so
+	// those of the whole package are known, with each file's own first.
+	pkgImports := make(map[string]string)
+	for _, fname := range files {
+		imports, err := fileImports(pkg.Files[fname], gopath)
+		if err != nil {
+			return nil, err
+		}
+		for imp, path := range imports {
+			pkgImports[imp] = path
+		}
+	}
+
+	for _, fname := range files {
+		logrus.WithField("file_name", fname).Println("parings file")
+
+		node := pkg.Files[fname]
+		if len(selects) > 0 {
+			ast.FilterFile(node, func(name string) bool {
+				for _, sel := range selects {
+					if name == sel {
+						return true
+					}
+				}
+				return false
+			})
+		}
+		pp := NewPackageParser(node.Name)
+		pp.scope = pkg.Scope.Objects
+		pp.expandAliases = config.expandAliases
+
+		importCache, err := fileImports(node, gopath)
+		if err != nil {
+			return nil, err
+		}
+		for imp, path := range pkgImports {
+			if _, ok := importCache[imp]; !ok {
+				importCache[imp] = path
+			}
+		}
+
+		// TODO: find a way to pass the scope to fix embedded interfaces
+		for _, d := range genDecls(node) {
+			specToks := interfaceSpecTokens(d)
+
+			for _, specTok := range specToks {
+				pp.imports = make(map[string]struct{})
+				pp.unexported = make(map[string]struct{})
+				pp.selfImport = false
+				ifce, err := pp.parseSpecToken(specTok)
+				if err != nil {
+					return nil, err
+				}
+				ok, err := fakeable(ifce)
+				if err != nil {
+					return nil, err
+				}
+				if !ok {
+					continue
+				}
+				ifce.Package = pkgName
+				ifce.PkgPath = pkgPath
+				ifce.Imports = pp.resolveImports(importCache, pkgPath)
+				ifce.Unexported = pp.unexportedTypes()
+
+				mock.Interfaces = append(mock.Interfaces, ifce)
+			}
+		}
+	}
+	mock.findSiblings()
+
+	return mock, nil
+}
+
+func gopathDir() string {
+	if gopath := os.Getenv("GOPATH"); gopath != "" {
+		return gopath
+	}
+	return build.Default.GOPATH
+}
+
+// parseDir parses the package in dir with the objects of all of its files
+// merged into the package scope. Tests, fakes written by this tool and files
+// ctxt excludes through their build constraints or their _GOOS and _GOARCH
+// suffixes are skipped. It returns the package, or nil if there is none,
+// along with its import path and the sorted names of its files.
+func parseDir(dir, gopath string, ctxt build.Context) (*ast.Package, string, []string, error) {
 	fset = token.NewFileSet()
-	noTests := func(f os.FileInfo) bool { return !strings.Contains(f.Name(), "_test") }
-	pkgs, err := parser.ParseDir(fset, dir, noTests, parser.AllErrors)
+	var matchErr error
+	matches := func(f os.FileInfo) bool {
+		if strings.HasSuffix(f.Name(), "_test.go") || matchErr != nil {
+			return false
+		}
+		match, err := ctxt.MatchFile(dir, f.Name())
+		if err != nil {
+			matchErr = err
+		}
+		return match
+	}
+	pkgs, err := parser.ParseDir(fset, dir, matches, parser.AllErrors|parser.ParseComments)
 	if err != nil {
-		panic(err)
+		return nil, "", nil, err
+	}
+	if matchErr != nil {
+		return nil, "", nil, matchErr
+	}
+	for _, pkg := range pkgs {
+		for fname, node := range pkg.Files {
+			if generated(node) {
+				delete(pkg.Files, fname)
+			}
+		}
+		if len(pkg.Files) == 0 {
+			delete(pkgs, pkg.Name)
+		}
 	}
 
 	// Don't know what this would mean. Should only have one package per read.
 	if len(pkgs) > 1 {
-		panic("too many packages")
+		var names []string
+		for name := range pkgs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, "", nil, fmt.Errorf("%s: found packages %s, expected only one", dir, strings.Join(names, ", "))
 	}
 
-	mock := new(Mock)
-	for pkgName, pkg := range pkgs {
-		logrus.WithFields(logrus.Fields{
-			"pkg_name":   pkgName,
-			"file_count": len(pkg.Files),
-		}).Println("parsing package")
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	pkgPath, err := importPath(abs, gopath)
+	if err != nil {
+		return nil, "", nil, err
+	}
 
+	for _, pkg := range pkgs {
 		pkg.Scope = ast.NewScope(nil)
 
-		// ast.Print(fset, pkg.Scope)
-
 		var files []string
-		for fname, node := range pkg.Files {
+		for fname := range pkg.Files {
 			files = append(files, fname)
-
-			for identity, obj := range node.Scope.Objects {
-				pkg.Scope.Objects[identity] = obj
-			}
 		}
 		sort.Strings(files)
 
 		for _, fname := range files {
-			logrus.WithField("file_name", fname).Println("parings file")
-
-			node := pkg.Files[fname]
-			if len(selects) > 0 {
-				ast.FilterFile(node, func(name string) bool {
-					for _, sel := range selects {
-						if name == sel {
-							return true
-						}
-					}
-					return false
-				})
+			for identity, obj := range pkg.Files[fname].Scope.Objects {
+				pkg.Scope.Objects[identity] = obj
 			}
-			pp := NewPackageParser(node.Name)
-			pp.scope = pkg.Scope.Objects
+		}
 
-			importCache := make(map[string]string)
-			for _, imp := range node.Imports {
-				dir := strings.Trim(imp.Path.Value, "\"")
-				pkg, err := build.Default.Import(dir, gopath, 0)
-				if err != nil {
-					panic(err)
-				}
+		return pkg, pkgPath, files, nil
+	}
 
-				importCache[pkg.Name] = dir
+	return nil, pkgPath, nil, nil
+}
+
+// generated reports whether node is a fake written by this tool, which isn't
+// read as a source of interfaces. Files other generators wrote may declare the
+// package's interfaces, so they are read like any other.
+func generated(node *ast.File) bool {
+	for _, group := range node.Comments {
+		if group.Pos() > node.Package {
+			break
+		}
+		for _, comment := range group.List {
+			if comment.Text == header {
+				return true
 			}
+		}
+	}
+	return false
+}
 
-			// TODO: find a way to pass the scope to fix embedded interfaces
-			for _, d := range genDecls(node) {
-				specToks := interfaceSpecTokens(d)
-
-				for _, specTok := range specToks {
-					pp.imports = make(map[string]struct{})
-					ifce := pp.parseInterfaceToken(specTok)
-					for imp := range pp.imports {
-						if pack, ok := importCache[imp]; ok {
-							ifce.Imports = append(ifce.Imports, pack)
-						}
-					}
-					if pp.selfImport {
-						abs, err := filepath.Abs(dir)
-						if err != nil {
-							panic("cannot make path to file")
-						}
-						rel, err := filepath.Rel(filepath.Join(gopath, "src"), abs)
-						if err != nil {
-							panic("cannot make find in go path")
-						}
-						ifce.Imports = append(ifce.Imports, rel)
-					}
+// fileImports maps the package names imported by node to their import paths.
+func fileImports(node *ast.File, gopath string) (map[string]string, error) {
+	importCache := make(map[string]string)
+	for _, imp := range node.Imports {
+		dir := strings.Trim(imp.Path.Value, "\"")
+		pkg, err := build.Default.Import(dir, gopath, 0)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", fset.Position(imp.Pos()), err)
+		}
 
-					mock.Interfaces = append(mock.Interfaces, ifce)
-				}
-			}
+		importCache[pkg.Name] = dir
+	}
 
+	return importCache, nil
+}
+
+// resolveImports returns the import paths of the packages the types parsed so
+// far refer to.
+func (pkg *packageParser) resolveImports(importCache map[string]string, pkgPath string) []string {
+	var imports []string
+	for imp := range pkg.imports {
+		if pack, ok := importCache[imp]; ok {
+			imports = append(imports, pack)
 		}
 	}
+	if pkg.selfImport && pkgPath != "" {
+		imports = append(imports, pkgPath)
+	}
 
-	return mock
+	return imports
+}
+
+// unexportedTypes returns the sorted names of the unexported types of the
+// package the types parsed so far refer to.
+func (pkg *packageParser) unexportedTypes() []string {
+	var names []string
+	for name := range pkg.unexported {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// importPath returns the import path of the package in dir. Packages inside a
+// module are resolved against the nearest go.mod, anything else is assumed to
+// live in the GOPATH. It returns an empty string when neither applies, and an
+// error when dir can't be made relative to the module holding it.
+func importPath(dir, gopath string) (string, error) {
+	for mod := dir; ; mod = filepath.Dir(mod) {
+		if modPath := modulePath(filepath.Join(mod, "go.mod")); modPath != "" {
+			rel, err := filepath.Rel(mod, dir)
+			if err != nil {
+				return "", fmt.Errorf("%s: cannot make an import path in module %s: %v", dir, modPath, err)
+			}
+			return path.Join(modPath, filepath.ToSlash(rel)), nil
+		}
+		if filepath.Dir(mod) == mod {
+			break
+		}
+	}
+
+	rel, err := filepath.Rel(filepath.Join(gopath, "src"), dir)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", nil
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// modulePath returns the module path declared in the go.mod file at name, or
+// an empty string if there is no such file.
+func modulePath(name string) string {
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		return ""
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], "\"`")
+		}
+	}
+	return ""
 }
 
 // ReadFile is the primary parser for a file to get mocked. This method walks
 // along the file ast to create a Mock object. Interfaces with embedded fields
-// outside of this file is not currently supported.
-func ReadFile(reader io.Reader) *Mock {
+// outside of this file is not currently supported. It returns an error for an
+// interface that can't be faked.
+func ReadFile(reader io.Reader) (*Mock, error) {
 	fset = token.NewFileSet()
 	node, err := parser.ParseFile(fset, "", reader, 0)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	mock := new(Mock)
@@ -181,13 +404,39 @@ func ReadFile(reader io.Reader) *Mock {
 	for _, d := range genDecls(node) {
 		specToks := interfaceSpecTokens(d)
 
-		pkg := new(packageParser)
+		pkg := NewPackageParser(node.Name)
+		pkg.imports = make(map[string]struct{})
 		for _, specTok := range specToks {
-			mock.Interfaces = append(mock.Interfaces, pkg.parseInterfaceToken(specTok))
+			ifce, err := pkg.parseSpecToken(specTok)
+			if err != nil {
+				return nil, err
+			}
+			ok, err := fakeable(ifce)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				mock.Interfaces = append(mock.Interfaces, ifce)
+			}
 		}
 	}
+	mock.findSiblings()
+
+	return mock, nil
+}
 
-	return mock
+// findSiblings tells every interface of the mock which others get their fakes
+// generated into the same package, the fakes package or their own.
+func (mock *Mock) findSiblings() {
+	for i, ifce := range mock.Interfaces {
+		var siblings []string
+		for j, other := range mock.Interfaces {
+			if i != j && other.Local() == ifce.Local() {
+				siblings = append(siblings, other.Name)
+			}
+		}
+		mock.Interfaces[i].siblings = siblings
+	}
 }
 
 func genDecls(node *ast.File) []*ast.GenDecl {
@@ -203,13 +452,15 @@ func genDecls(node *ast.File) []*ast.GenDecl {
 }
 
 // interfaceTokens returns ast.TypeSpec becuase the name of the interface can
-// only be pulled from the TypeSpec.
+// only be pulled from the TypeSpec. Named func types are returned too, since
+// they get faked like single method interfaces.
 func interfaceSpecTokens(node *ast.GenDecl) []*ast.TypeSpec {
 	toks := []*ast.TypeSpec{}
 
 	for _, spec := range node.Specs {
 		if tspec, ok := spec.(*ast.TypeSpec); ok {
-			if _, ok := tspec.Type.(*ast.InterfaceType); ok {
+			switch tspec.Type.(type) {
+			case *ast.InterfaceType, *ast.FuncType:
 				toks = append(toks, tspec)
 			}
 		}
@@ -218,52 +469,240 @@ func interfaceSpecTokens(node *ast.GenDecl) []*ast.TypeSpec {
 	return toks
 }
 
-func (pkg *packageParser) parseInterfaceToken(tok *ast.TypeSpec) Interface {
+// fakeable reports whether a fake can be generated for ifce. Constraint
+// interfaces and interfaces embedding one that can't be read are skipped with
+// a warning. An interface mixing methods with type terms can't be implemented
+// by any fake, so an error is returned instead.
+func fakeable(ifce Interface) (bool, error) {
+	if ifce.foreign != nil {
+		logrus.WithFields(logrus.Fields{
+			"interface": ifce.Name,
+			"embedded":  types.ExprString(ifce.foreign.Type),
+			"position":  fset.Position(ifce.foreign.Pos()),
+		}).Warnln("skipping interface embedding an interface that cannot be read")
+		return false, nil
+	}
+	if !ifce.typeTerms.IsValid() {
+		return true, nil
+	}
+	if len(ifce.Methods) > 0 {
+		return false, fmt.Errorf("%s: interface %s mixes methods with type terms and cannot be faked", fset.Position(ifce.typeTerms), ifce.Name)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"interface": ifce.Name,
+		"position":  fset.Position(ifce.typeTerms),
+	}).Warnln("skipping constraint interface")
+	return false, nil
+}
+
+// parseSpecToken parses a type spec returned by interfaceSpecTokens.
+func (pkg *packageParser) parseSpecToken(tok *ast.TypeSpec) (Interface, error) {
+	if _, ok := tok.Type.(*ast.FuncType); ok {
+		return pkg.parseFuncTypeToken(tok), nil
+	}
+	return pkg.parseInterfaceToken(tok)
+}
+
+// parseFuncTypeToken reads a named func type as an interface with the single
+// method Call, which takes the args and returns the results of the func.
+func (pkg *packageParser) parseFuncTypeToken(tok *ast.TypeSpec) Interface {
+	defer func(outer map[string]struct{}) { pkg.typeParams = outer }(pkg.typeParams)
+	pkg.typeParams = make(map[string]struct{})
+
+	typeParams := pkg.parseTypeParams(tok.TypeParams)
+
+	method := Method{Name: funcMethod}
+	method.Args, method.Rets = pkg.parseFuncToken(tok.Type.(*ast.FuncType))
+
+	return Interface{Name: tok.Name.Name, TypeParams: typeParams, Methods: []Method{method}, Func: true}
+}
+
+// parseInterfaceToken reads the methods of an interface type, along with those
+// of the interfaces it embeds. It returns an error for an embedding cycle or
+// conflicting methods.
+func (pkg *packageParser) parseInterfaceToken(tok *ast.TypeSpec) (Interface, error) {
 	itfcTok := tok.Type.(*ast.InterfaceType)
 	methods := []Method{}
 
+	for i, outer := range pkg.embedding {
+		if outer != tok {
+			continue
+		}
+		cycle := []string{}
+		for _, spec := range append(pkg.embedding[i:], tok) {
+			cycle = append(cycle, spec.Name.Name)
+		}
+		return Interface{}, fmt.Errorf("%s: interface %s embeds itself: %s", fset.Position(tok.Pos()), tok.Name.Name, strings.Join(cycle, " -> "))
+	}
+	pkg.embedding = append(pkg.embedding, tok)
+	defer func() { pkg.embedding = pkg.embedding[:len(pkg.embedding)-1] }()
+
+	// The type parameters have to be known before the methods are parsed so
+	// that they aren't mistaken for package types. Embedded interfaces get a
+	// scope of their own.
+	defer func(outer map[string]struct{}) { pkg.typeParams = outer }(pkg.typeParams)
+	pkg.typeParams = make(map[string]struct{})
+
+	typeParams := pkg.parseTypeParams(tok.TypeParams)
+
+	var typeTerms token.Pos
+	var foreign *ast.Field
 	for _, methTok := range itfcTok.Methods.List {
-		if specType, ok := embeddedInterface(methTok, pkg.scope); ok {
-			embedded := pkg.parseInterfaceToken(specType)
-			methods = append(methods, embedded.Methods...)
+		if isAny(methTok) {
+			continue
+		}
+		if typeTerm(methTok, pkg.scope) {
+			if !typeTerms.IsValid() {
+				typeTerms = methTok.Pos()
+			}
+			continue
+		}
+		if len(methTok.Names) == 0 {
+			specTok, ok := pkg.embeddedInterface(methTok.Type)
+			if !ok {
+				if foreign == nil {
+					foreign = methTok
+				}
+				continue
+			}
+			embedded, err := pkg.parseInterfaceToken(specTok)
+			if err != nil {
+				return Interface{}, err
+			}
+			for _, method := range embedded.Methods {
+				if methods, err = mergeMethod(tok, methods, method); err != nil {
+					return Interface{}, err
+				}
+			}
+			if embedded.typeTerms.IsValid() && !typeTerms.IsValid() {
+				typeTerms = methTok.Pos()
+			}
+			if embedded.foreign != nil && foreign == nil {
+				foreign = embedded.foreign
+			}
 			continue
 		}
-		methods = append(methods, pkg.parseMethodToken(methTok))
+		var err error
+		if methods, err = mergeMethod(tok, methods, pkg.parseMethodToken(methTok)); err != nil {
+			return Interface{}, err
+		}
 	}
 
-	return Interface{Name: tok.Name.Name, Methods: methods}
+	return Interface{Name: tok.Name.Name, TypeParams: typeParams, Methods: methods, typeTerms: typeTerms, foreign: foreign}, nil
+}
+
+// isAny reports whether tok embeds any, which adds nothing to an interface.
+func isAny(tok *ast.Field) bool {
+	ident, ok := tok.Type.(*ast.Ident)
+	return ok && len(tok.Names) == 0 && ident.Name == "any" && ident.Obj == nil
+}
+
+// predeclared holds the predeclared types that can only be embedded as type
+// terms. Of the predeclared interfaces, any is read apart and error can't be
+// read.
+var predeclared = map[string]bool{
+	"bool": true, "byte": true, "comparable": true, "complex64": true, "complex128": true,
+	"float32": true, "float64": true, "int": true, "int8": true, "int16": true,
+	"int32": true, "int64": true, "rune": true, "string": true, "uint": true,
+	"uint8": true, "uint16": true, "uint32": true, "uint64": true, "uintptr": true,
 }
 
-// embeddedInterface returns any embedded interfaces or false otherwise. An
-// embedded interface can be determined by checking the Names attribute. Methods
-// have a Names tokens, whereas embedded interfaces do not.
-func embeddedInterface(tok *ast.Field, scope map[string]*ast.Object) (*ast.TypeSpec, bool) {
+// typeTerm reports whether tok is a type term of a constraint, such as ~int,
+// int | float64, comparable or a named non-interface type, rather than a
+// method or an embedded interface.
+func typeTerm(tok *ast.Field, scope map[string]*ast.Object) bool {
 	if len(tok.Names) > 0 {
-		return nil, false
+		return false
 	}
 
 	switch tokType := tok.Type.(type) {
+	case *ast.UnaryExpr, *ast.BinaryExpr:
+		return true
 	case *ast.Ident:
 		obj := tokType.Obj
-		if tokType.Obj == nil {
+		if obj == nil {
 			obj = scope[tokType.Name]
 		}
-
-		if specTok, ok := obj.Decl.(*ast.TypeSpec); ok {
-			_, ok := specTok.Type.(*ast.InterfaceType)
-			return specTok, ok
+		if obj == nil {
+			return predeclared[tokType.Name]
 		}
-	case *ast.SelectorExpr:
+		specTok, ok := obj.Decl.(*ast.TypeSpec)
+		if !ok {
+			return false
+		}
+		_, ok = specTok.Type.(*ast.InterfaceType)
+		return !ok
+	}
+	return false
+}
 
-		// ifaceName := tokType.Sel.Name
+// embeddedInterface returns the declaration of the interface typ names if
+// it's one declared in the package that can be read. Interfaces of other
+// packages, like io.Reader, the predeclared error and instantiated generic
+// interfaces can't be.
+func (pkg *packageParser) embeddedInterface(typ ast.Expr) (*ast.TypeSpec, bool) {
+	ident, ok := typ.(*ast.Ident)
+	if !ok {
+		return nil, false
+	}
+	obj := ident.Obj
+	if obj == nil {
+		obj = pkg.scope[ident.Name]
+	}
+	if obj == nil {
+		return nil, false
+	}
 
+	specTok, ok := obj.Decl.(*ast.TypeSpec)
+	if !ok || specTok.TypeParams != nil {
+		return nil, false
 	}
+	_, ok = specTok.Type.(*ast.InterfaceType)
+	return specTok, ok
+}
 
-	return nil, false
+// mergeMethod adds method to the methods of the interface tok unless it's
+// already there, as it may be when embedded interfaces overlap. It returns an
+// error if the interface has a method of the same name with another signature.
+func mergeMethod(tok *ast.TypeSpec, methods []Method, method Method) ([]Method, error) {
+	for _, known := range methods {
+		if known.Name != method.Name {
+			continue
+		}
+		knownSig, err := known.signature()
+		if err != nil {
+			return nil, err
+		}
+		sig, err := method.signature()
+		if err != nil {
+			return nil, err
+		}
+		if knownSig != sig {
+			return nil, fmt.Errorf("%s: interface %s has conflicting methods %s: %s at %s and %s at %s",
+				fset.Position(tok.Pos()), tok.Name.Name, method.Name,
+				knownSig, fset.Position(known.pos), sig, fset.Position(method.pos))
+		}
+		return methods, nil
+	}
+	return append(methods, method), nil
+}
+
+// signature returns the Go source of the method's type, without the arg and
+// result names, which don't make two signatures differ.
+func (method Method) signature() (string, error) {
+	params, results := fieldList(), fieldList()
+	for _, arg := range method.Args {
+		params.List = append(params.List, field(arg.Type))
+	}
+	for _, ret := range method.Rets {
+		results.List = append(results.List, field(ret.Type))
+	}
+	return typeString(&ast.FuncType{Params: params, Results: results})
 }
 
 func (pkg *packageParser) parseMethodToken(tok *ast.Field) Method {
-	var method Method
+	method := Method{pos: tok.Pos()}
 
 	for _, idenTok := range tok.Names {
 		method.Name = idenTok.Name
@@ -351,7 +790,20 @@ func (pkg *packageParser) parseType(tok ast.Expr) (string, ast.Expr) {
 	switch typeTok := tok.(type) {
 	case *ast.Ident:
 		name := typeTok.Name
-		if _, ok := pkg.scope[name]; ok {
+		if _, ok := pkg.typeParams[name]; ok {
+			return lowerFirst(name), ast.NewIdent(name)
+		}
+		if obj, ok := pkg.scope[name]; ok {
+			if spec, ok := obj.Decl.(*ast.TypeSpec); ok && spec.Assign.IsValid() && pkg.expandAliases {
+				_, expr := pkg.parseType(spec.Type)
+				return lowerFirst(name), expr
+			}
+			if !ast.IsExported(name) {
+				if pkg.unexported == nil {
+					pkg.unexported = make(map[string]struct{})
+				}
+				pkg.unexported[name] = struct{}{}
+			}
 			pkg.selfImport = true
 			return lowerFirst(name), &ast.SelectorExpr{X: ast.NewIdent(pkg.pkg.Name), Sel: ast.NewIdent(typeTok.Name)}
 		}
@@ -373,8 +825,75 @@ func (pkg *packageParser) parseType(tok ast.Expr) (string, ast.Expr) {
 		if !strings.HasSuffix(name, "Arr") {
 			name += "Arr"
 		}
-		return name, &ast.ArrayType{Elt: expr}
+		return name, &ast.ArrayType{Len: typeTok.Len, Elt: expr}
+	case *ast.StarExpr:
+		name, expr := pkg.parseType(typeTok.X)
+		return name + "Ptr", &ast.StarExpr{X: expr}
+	case *ast.MapType:
+		_, key := pkg.parseType(typeTok.Key)
+		name, value := pkg.parseType(typeTok.Value)
+		return name + "Map", &ast.MapType{Key: key, Value: value}
+	case *ast.ChanType:
+		name, expr := pkg.parseType(typeTok.Value)
+		return name + "Chan", &ast.ChanType{Dir: typeTok.Dir, Value: expr}
+	case *ast.FuncType:
+		return "func", &ast.FuncType{
+			Params:  pkg.parseFieldList(typeTok.Params),
+			Results: pkg.parseFieldList(typeTok.Results),
+		}
+	case *ast.InterfaceType:
+		// The printer only keeps the braces of an empty literal on one line
+		// when it knows their positions, so these are kept as plain names.
+		if typeTok.Methods.NumFields() == 0 {
+			return "iface", ast.NewIdent("interface{}")
+		}
+		return "iface", &ast.InterfaceType{Methods: pkg.parseFieldList(typeTok.Methods)}
+	case *ast.StructType:
+		if typeTok.Fields.NumFields() == 0 {
+			return "struct", ast.NewIdent("struct{}")
+		}
+		return "struct", &ast.StructType{Fields: pkg.parseFieldList(typeTok.Fields)}
+	case *ast.ParenExpr:
+		return pkg.parseType(typeTok.X)
+	case *ast.IndexExpr:
+		name, expr := pkg.parseType(typeTok.X)
+		_, index := pkg.parseType(typeTok.Index)
+		return name, &ast.IndexExpr{X: expr, Index: index}
+	case *ast.IndexListExpr:
+		name, expr := pkg.parseType(typeTok.X)
+		var indices []ast.Expr
+		for _, indexTok := range typeTok.Indices {
+			_, index := pkg.parseType(indexTok)
+			indices = append(indices, index)
+		}
+		return name, &ast.IndexListExpr{X: expr, Indices: indices}
+	case *ast.UnaryExpr:
+		name, expr := pkg.parseType(typeTok.X)
+		return name, &ast.UnaryExpr{Op: typeTok.Op, X: expr}
+	case *ast.BinaryExpr:
+		name, x := pkg.parseType(typeTok.X)
+		_, y := pkg.parseType(typeTok.Y)
+		return name, &ast.BinaryExpr{X: x, Op: typeTok.Op, Y: y}
 	}
 
 	return "", nil
 }
+
+// parseFieldList copies the params, results, methods or fields of a type
+// literal with each of their types parsed like a method's args.
+func (pkg *packageParser) parseFieldList(tok *ast.FieldList) *ast.FieldList {
+	if tok == nil {
+		return nil
+	}
+
+	list := &ast.FieldList{}
+	for _, fieldTok := range tok.List {
+		f := &ast.Field{}
+		for _, idenTok := range fieldTok.Names {
+			f.Names = append(f.Names, ast.NewIdent(idenTok.Name))
+		}
+		_, f.Type = pkg.parseType(fieldTok.Type)
+		list.List = append(list.List, f)
+	}
+	return list
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"go/ast"
+	"go/types"
 	"io"
 	"io/ioutil"
 	"os"
@@ -42,6 +43,14 @@ func TestReadPkg(t *testing.T) {
 			f.Close()
 		}
 	}
+	goMod := func(module string) pkgMaker {
+		return func(dir string, i int) {
+			p := filepath.Join(dir, "go.mod")
+			if err := ioutil.WriteFile(p, []byte("module "+module+"\n"), 0644); err != nil {
+				panic(err)
+			}
+		}
+	}
 
 	type checkOut func(*Mock) []error
 	check := func(fns ...checkOut) []checkOut { return fns }
@@ -88,6 +97,24 @@ func TestReadPkg(t *testing.T) {
 			return nil
 		}
 	}
+	interfaceHasPackage := func(name, path string) checkOutInterface {
+		return func(iface Interface) []error {
+			var errs []error
+			if iface.Package != name {
+				errs = append(errs, fmt.Errorf(
+					"expected to have package %q but got %q",
+					name, iface.Package,
+				))
+			}
+			if iface.PkgPath != path {
+				errs = append(errs, fmt.Errorf(
+					"expected to have package path %q but got %q",
+					path, iface.PkgPath,
+				))
+			}
+			return errs
+		}
+	}
 	interfaceHasMethodCount := func(count int) checkOutInterface {
 		return func(iface Interface) []error {
 			if len(iface.Methods) != count {
@@ -268,6 +295,22 @@ func TestReadPkg(t *testing.T) {
 					),
 				),
 			),
+		}, {
+			"Interface in a module",
+			pkg(goMod("example.com/a"), file(`
+				package a
+
+				type B interface{
+					C()
+				}`,
+			)),
+			check(
+				expectInterfaceCount(1),
+				checkInterface(0,
+					interfaceHasName("B"),
+					interfaceHasPackage("a", "example.com/a"),
+				),
+			),
 		}, {
 			"Unnamed args",
 			pkg(file(`
@@ -441,6 +484,143 @@ func TestReadPkg(t *testing.T) {
 			// 			),
 			// 		),
 			// 	),
+		}, {
+			"Named func type",
+			pkg(file(`
+				package a
+
+				type B func(c string) (int, error)`,
+			)),
+			check(
+				expectInterfaceCount(1),
+				checkInterface(0,
+					interfaceHasName("B"),
+					interfaceHasMethodCount(1),
+					checkMethod(0,
+						methodHasName("Call"),
+						methodHasArgCount(1),
+						checkArgs(
+							checkValue("c", stringType),
+						),
+						methodHasRetCount(2),
+						checkRets(
+							checkValue("intResult", intType),
+							checkValue("errResult", errorType),
+						),
+					),
+				),
+			),
+		}, {
+			"Overlapping embedded interfaces",
+			pkg(file(`
+				package a
+
+				type B interface {
+					C(d string) error
+				}
+
+				type E interface {
+					C(string) error
+					F()
+				}
+
+				type G interface {
+					B
+					E
+					C(h string) (i error)
+				}`,
+			)),
+			check(
+				expectInterfaceCount(3),
+				checkInterface(2,
+					interfaceHasName("G"),
+					interfaceHasMethodCount(2),
+					checkMethod(0,
+						methodHasName("C"),
+						checkArgs(
+							checkValue("d", stringType),
+						),
+					),
+					checkMethod(1,
+						methodHasName("F"),
+					),
+				),
+			),
+		}, {
+			"Constraint interfaces",
+			pkg(file(`
+				package a
+
+				type B interface {
+					~int | ~float64
+				}
+
+				type C interface {
+					B
+					comparable
+				}
+
+				type D[T C] interface {
+					any
+					E(T) T
+				}`,
+			)),
+			check(
+				expectInterfaceCount(1),
+				checkInterface(0,
+					interfaceHasName("D"),
+					interfaceHasMethodCount(1),
+					checkMethod(0,
+						methodHasName("E"),
+					),
+				),
+			),
+		}, {
+			"Interfaces embedding unreadable interfaces",
+			pkg(file(`
+				package a
+
+				import "io"
+
+				type B interface {
+					io.Reader
+					C()
+				}
+
+				type D interface {
+					error
+				}
+
+				type E[T any] interface {
+					F(T)
+				}
+
+				type G interface {
+					E[string]
+				}
+
+				type H interface {
+					B
+				}
+
+				type I interface {
+					J() string
+				}`,
+			)),
+			check(
+				expectInterfaceCount(2),
+				checkInterface(0,
+					interfaceHasName("E"),
+					interfaceHasMethodCount(1),
+				),
+				checkInterface(1,
+					interfaceHasName("I"),
+					interfaceHasMethodCount(1),
+					checkMethod(0,
+						methodHasName("J"),
+					),
+				),
+			),
 		}, {
 			"Variadic args",
 			pkg(file(`
@@ -517,7 +697,7 @@ func TestReadPkg(t *testing.T) {
 
 				type B interface{
 					C([][]string, []bytes.Buffer) []error
-				`,
+				}`,
 			)),
 			check(
 				expectInterfaceCount(1),
@@ -598,7 +778,10 @@ func TestReadPkg(t *testing.T) {
 			dir := tt.input
 			defer os.RemoveAll(dir)
 
-			mocks := ReadPkg(dir, nil)
+			mocks, err := ReadPkg(dir, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
 			for _, check := range tt.checks {
 				for _, checkErr := range check(mocks) {
 					if checkErr != nil {
@@ -609,3 +792,363 @@ func TestReadPkg(t *testing.T) {
 		})
 	}
 }
+
+func TestReadFile(t *testing.T) {
+	const input = `package a
+
+import (
+	"io"
+	"time"
+)
+
+type B interface {
+	C(d time.Duration, r io.Reader) []time.Time
+}`
+
+	mock, err := ReadFile(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mock.Interfaces) != 1 || len(mock.Interfaces[0].Methods) != 1 {
+		t.Fatalf("expected one interface with one method but got %+v", mock.Interfaces)
+	}
+	var typs []string
+	meth := mock.Interfaces[0].Methods[0]
+	for _, val := range append(meth.Args, meth.Rets...) {
+		typs = append(typs, types.ExprString(val.Type))
+	}
+	if expect := []string{"time.Duration", "io.Reader", "[]time.Time"}; !reflect.DeepEqual(typs, expect) {
+		t.Errorf("expected the types %v but got %v", expect, typs)
+	}
+}
+
+func TestReadFileErrors(t *testing.T) {
+	tests := [...]struct {
+		name   string
+		input  string
+		expect string
+	}{
+		{
+			"Conflicting embedded methods",
+			`package a
+
+type B interface {
+	C(string) error
+}
+
+type D interface {
+	C(int) error
+}
+
+type E interface {
+	B
+	D
+}`,
+			"11:6: interface E has conflicting methods C: func(string) error at 4:2 and func(int) error at 8:2",
+		}, {
+			"Conflicting declared method",
+			`package a
+
+type B interface {
+	C(...string)
+}
+
+type D interface {
+	B
+	C([]string)
+}`,
+			"7:6: interface D has conflicting methods C: func(...string) at 4:2 and func([]string) at 9:2",
+		}, {
+			"Embedding cycle",
+			`package a
+
+type B interface {
+	D
+}
+
+type D interface {
+	E
+}
+
+type E interface {
+	B
+}`,
+			"3:6: interface B embeds itself: B -> D -> E -> B",
+		}, {
+			"Methods and type terms",
+			`package a
+
+type B interface {
+	C() string
+	~string
+}`,
+			"5:2: interface B mixes methods with type terms and cannot be faked",
+		}, {
+			"Methods and an embedded constraint",
+			`package a
+
+type B interface {
+	int | string
+}
+
+type C interface {
+	D()
+	B
+}`,
+			"9:2: interface C mixes methods with type terms and cannot be faked",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadFile(strings.NewReader(tt.input))
+			if err == nil || err.Error() != tt.expect {
+				t.Errorf("expected error %q but got %v", tt.expect, err)
+			}
+		})
+	}
+}
+
+func TestReadPkgErrors(t *testing.T) {
+	tests := [...]struct {
+		name   string
+		files  map[string]string
+		expect string
+	}{
+		{
+			"Two packages",
+			map[string]string{"a.go": "package a", "b.go": "package b"},
+			"found packages a, b, expected only one",
+		}, {
+			"Missing import",
+			map[string]string{"a.go": `package a
+
+import "example.com/missing"
+
+type B interface {
+	C() missing.D
+}`},
+			"a.go:3:8: ",
+		}, {
+			"Conflicting embedded methods",
+			map[string]string{"a.go": `package a
+
+type B interface {
+	C(string)
+}
+
+type D interface {
+	B
+	C(int)
+}`},
+			"a.go:7:6: interface D has conflicting methods C",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "read_errors_")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+			for name, src := range tt.files {
+				if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			_, err = ReadPkg(dir, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.expect) {
+				t.Errorf("expected error containing %q but got %v", tt.expect, err)
+			}
+		})
+	}
+}
+
+func TestReadPkgAliases(t *testing.T) {
+	const input = `package a
+
+import "time"
+
+type ID = string
+
+type Status int
+
+type token struct{}
+
+type Token = token
+
+type Timeout = time.Duration
+
+type B interface {
+	C(ID, Status, []ID) Timeout
+}
+
+type D interface {
+	E(Token)
+}`
+
+	tests := [...]struct {
+		name       string
+		opts       []ReadOption
+		expect     []string
+		unexported []string
+	}{
+		{
+			"Aliases kept",
+			nil,
+			[]string{"a.ID", "a.Status", "[]a.ID", "a.Timeout", "a.Token"},
+			nil,
+		}, {
+			"Aliases expanded",
+			[]ReadOption{ExpandAliases()},
+			[]string{"string", "a.Status", "[]string", "time.Duration", "a.token"},
+			[]string{"token"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "read_aliases_")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+			if err := ioutil.WriteFile(filepath.Join(dir, "a.go"), []byte(input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			mock, err := ReadPkg(dir, nil, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(mock.Interfaces) != 2 {
+				t.Fatalf("expected 2 interfaces but got %d", len(mock.Interfaces))
+			}
+			var typs []string
+			for _, ifce := range mock.Interfaces {
+				for _, meth := range ifce.Methods {
+					for _, val := range append(meth.Args, meth.Rets...) {
+						typs = append(typs, types.ExprString(val.Type))
+					}
+				}
+			}
+			if !reflect.DeepEqual(typs, tt.expect) {
+				t.Errorf("expected the types %v but got %v", tt.expect, typs)
+			}
+			if unexported := mock.Interfaces[1].Unexported; !reflect.DeepEqual(unexported, tt.unexported) {
+				t.Errorf("expected the unexported types %v but got %v", tt.unexported, unexported)
+			}
+			if unexported := mock.Interfaces[0].Unexported; unexported != nil {
+				t.Errorf("expected no unexported types for %s but got %v", mock.Interfaces[0].Name, unexported)
+			}
+		})
+	}
+}
+
+func TestReadPkgBuildConstraints(t *testing.T) {
+	files := map[string]string{
+		"a.go": `package a
+
+type B interface {
+	C()
+}`,
+		"a_linux.go": `package a
+
+type D interface {
+	Linux()
+}`,
+		"a_windows.go": `package a
+
+type D interface {
+	Windows()
+}`,
+		"a_test.go": `package a
+
+type T interface {
+	Test()
+}`,
+		"a_testing.go": `package a
+
+type H interface {
+	Testing()
+}`,
+		"extra.go": `//go:build extra
+
+package a
+
+type E interface {
+	Extra()
+}`,
+		"tool.go": `//go:build ignore
+
+package main
+
+type F interface {
+	Tool()
+}`,
+		"zz_generated.go": `// Code generated by stringer. DO NOT EDIT.
+
+package a
+
+type G interface {
+	Generated()
+}`,
+		"zz_fake_b.go": `// generated by table-mocks; DO NOT EDIT
+
+package a
+
+type fakeBCFunc func()
+`,
+	}
+
+	tests := [...]struct {
+		name   string
+		opts   []ReadOption
+		expect []string
+	}{
+		{
+			"Linux",
+			[]ReadOption{GOOS("linux")},
+			[]string{"B.C", "D.Linux", "H.Testing", "G.Generated"},
+		}, {
+			"Windows",
+			[]ReadOption{GOOS("windows"), GOARCH("amd64")},
+			[]string{"B.C", "H.Testing", "D.Windows", "G.Generated"},
+		}, {
+			"Tags",
+			[]ReadOption{GOOS("linux"), BuildTags("extra")},
+			[]string{"B.C", "D.Linux", "H.Testing", "E.Extra", "G.Generated"},
+		},
+	}
+
+	dir, err := ioutil.TempDir("", "read_constraints_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for name, src := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := ReadPkg(dir, nil, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var methods []string
+			for _, ifce := range mock.Interfaces {
+				for _, meth := range ifce.Methods {
+					methods = append(methods, ifce.Name+"."+meth.Name)
+				}
+			}
+			if !reflect.DeepEqual(methods, tt.expect) {
+				t.Errorf("expected the methods %v but got %v", tt.expect, methods)
+			}
+		})
+	}
+}
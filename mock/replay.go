@@ -0,0 +1,112 @@
+package mock
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// generateReplay returns the Record and LoadReplay methods, named by
+// helperName, which write the recorded calls of every method as JSON and
+// program a fake from them.
+func (ifce Interface) generateReplay() []ast.Decl {
+	return []ast.Decl{
+		ifce.generateRecord(),
+		ifce.generateLoadReplay(),
+	}
+}
+
+// recordingMap returns the map WriteRecording and ReadRecording take, holding
+// the map of every method's per call structs by its qualified name.
+func (ifce Interface) recordingMap(methodMap func(Method) ast.Expr) *ast.CompositeLit {
+	lit := &ast.CompositeLit{
+		Type: &ast.MapType{Key: ast.NewIdent("string"), Value: ast.NewIdent("interface{}")},
+	}
+	for _, method := range ifce.Methods {
+		lit.Elts = append(lit.Elts, &ast.KeyValueExpr{Key: method.assertName(ifce), Value: methodMap(method)})
+	}
+
+	return lit
+}
+
+func (ifce Interface) generateRecord() *ast.FuncDecl {
+	snapshot := ast.NewIdent("snapshot")
+
+	body := blockStmt(
+		&ast.AssignStmt{
+			Lhs: expression(snapshot),
+			Tok: token.DEFINE,
+			Rhs: expression(call(selectorExpr(ast.NewIdent("fake"), ifce.helperName("Snapshot")))),
+		},
+		&ast.ReturnStmt{Results: expression(call(
+			selectorExpr(ast.NewIdent("tablemock"), "WriteRecording"),
+			ast.NewIdent("w"),
+			ifce.recordingMap(func(method Method) ast.Expr {
+				return selectorExpr(snapshot, method.recordName())
+			}),
+		))},
+	)
+
+	recv := ifce.recv()
+	params := fieldList(field(selectorExpr(ast.NewIdent("io"), "Writer"), "w"))
+	results := fieldList(field(ast.NewIdent("error")))
+
+	return funcDecl(recv, ifce.helperName("Record"), params, results, body)
+}
+
+// generateLoadReplay returns LoadReplay, which reads the whole recording
+// before programming any method so that a bad recording changes nothing.
+func (ifce Interface) generateLoadReplay() *ast.FuncDecl {
+	fake := ast.NewIdent("fake")
+	err := ast.NewIdent("err")
+
+	body := blockStmt()
+	for _, method := range ifce.Methods {
+		body.List = append(body.List, &ast.AssignStmt{
+			Lhs: expression(ast.NewIdent(method.fieldName())),
+			Tok: token.DEFINE,
+			Rhs: expression(call(ast.NewIdent("make"), ifce.methodMap(method))),
+		})
+	}
+	body.List = append(body.List, &ast.IfStmt{
+		Init: &ast.AssignStmt{
+			Lhs: expression(err),
+			Tok: token.DEFINE,
+			Rhs: expression(call(
+				selectorExpr(ast.NewIdent("tablemock"), "ReadRecording"),
+				ast.NewIdent("r"),
+				ifce.recordingMap(func(method Method) ast.Expr {
+					return ast.NewIdent(method.fieldName())
+				}),
+			)),
+		},
+		Cond: &ast.BinaryExpr{X: err, Op: token.NEQ, Y: ast.NewIdent("nil")},
+		Body: blockStmt(&ast.ReturnStmt{Results: expression(err)}),
+	})
+	for i, method := range ifce.Methods {
+		fakeMethodMutex := selectorExpr(fake, method.mutexName())
+		var lock ast.Stmt = exprStmt(call(selectorExpr(fakeMethodMutex, "Lock")))
+		if i == 0 {
+			lock = spaced(lock).(ast.Stmt)
+		}
+		body.List = append(body.List,
+			lock,
+			&ast.RangeStmt{
+				Key: ast.NewIdent("call"), Value: ast.NewIdent("fakeMethod"),
+				Tok: token.DEFINE,
+				X:   ast.NewIdent(method.fieldName()),
+				Body: blockStmt(assign(
+					&ast.IndexExpr{X: selectorExpr(fake, method.fieldName()), Index: ast.NewIdent("call")},
+					ast.NewIdent("fakeMethod"),
+				)),
+			},
+			exprStmt(call(selectorExpr(fakeMethodMutex, "Unlock"))),
+		)
+	}
+	body.List = append(body.List, &ast.ReturnStmt{Results: expression(ast.NewIdent("nil"))})
+
+	recv := ifce.recv()
+	params := fieldList(field(selectorExpr(ast.NewIdent("io"), "Reader"), "r"))
+	results := fieldList(field(ast.NewIdent("error")))
+
+	return funcDecl(recv, ifce.helperName("LoadReplay"), params, results, body)
+}
@@ -0,0 +1,234 @@
+package mock
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// snapshotName names the type Snapshot returns, like RunnerSnapshot. It gets
+// the Fake suffix while it clashes with the fake of a sibling interface, such
+// as one named RunnerSnapshot.
+func (ifce Interface) snapshotName() string {
+	fakes := make(map[string]bool)
+	for _, sibling := range ifce.siblings {
+		fakes[Interface{Name: sibling, inPkg: ifce.inPkg}.fakeName()] = true
+	}
+
+	name := ifce.fakeName() + "Snapshot"
+	for fakes[name] {
+		name += "Fake"
+	}
+	return name
+}
+
+// generateResets returns the Reset, ResetCalls, Snapshot and Restore methods,
+// named by helperName, along with the snapshot type they share. Every method's
+// state is touched only while holding that method's mutex. Reset also releases
+// blocked calls.
+func (ifce Interface) generateResets() []ast.Decl {
+	return []ast.Decl{
+		ifce.generateReset(),
+		ifce.generateResetCalls(),
+		ifce.generateSnapshotStruct(),
+		ifce.generateSnapshot(),
+		ifce.generateRestore(),
+	}
+}
+
+func (ifce Interface) generateReset() *ast.FuncDecl {
+	fake := ast.NewIdent("fake")
+
+	body := blockStmt()
+	for _, method := range ifce.Methods {
+		fakeMethodMutex := selectorExpr(fake, method.mutexName())
+		body.List = append(body.List,
+			exprStmt(call(selectorExpr(fakeMethodMutex, "Lock"))),
+			assign(selectorExpr(fake, method.fieldName()), call(ast.NewIdent("make"), ifce.methodMap(method))),
+			assign(selectorExpr(fake, method.recordName()), call(ast.NewIdent("make"), ifce.methodMap(method))),
+			assign(selectorExpr(fake, method.whenFieldName()), ast.NewIdent("nil")),
+		)
+		if _, ok := method.errorResult(); ok {
+			body.List = append(body.List,
+				assign(selectorExpr(fake, method.failsName()), &ast.CompositeLit{
+					Type: selectorExpr(ast.NewIdent("tablemock"), "Every"),
+				}),
+				assign(selectorExpr(fake, method.failuresName()), ast.NewIdent("nil")),
+			)
+		}
+		body.List = append(body.List,
+			assign(selectorExpr(fake, method.panicsName()), ast.NewIdent("nil")),
+			assign(selectorExpr(fake, method.delayName()), &ast.BasicLit{Kind: token.INT, Value: "0"}),
+			assign(selectorExpr(fake, method.delaysName()), ast.NewIdent("nil")),
+			assign(selectorExpr(fake, method.sequenceName()), &ast.CompositeLit{
+				Type: selectorExpr(ast.NewIdent("tablemock"), "Sequence"),
+			}),
+		)
+		if method.setsArgs(ifce) {
+			body.List = append(body.List, assign(selectorExpr(fake, method.setsName()), ast.NewIdent("nil")))
+		}
+		body.List = append(body.List,
+			assign(selectorExpr(fake, method.callsName()), &ast.BasicLit{Kind: token.INT, Value: "0"}),
+			method.countCalls(),
+			exprStmt(call(selectorExpr(fakeMethodMutex, "Unlock"))),
+			exprStmt(call(selectorExpr(selectorExpr(fake, method.gateName()), "Release"))),
+		)
+	}
+	body.List = append(body.List, exprStmt(call(
+		selectorExpr(selectorExpr(selectorExpr(fake, "opts"), "Recorder"), "Reset"), fake,
+	)))
+
+	recv := ifce.recv()
+
+	return funcDecl(recv, ifce.helperName("Reset"), fieldList(), fieldList(), body)
+}
+
+func (ifce Interface) generateResetCalls() *ast.FuncDecl {
+	fake := ast.NewIdent("fake")
+
+	body := blockStmt()
+	for _, method := range ifce.Methods {
+		fakeMethodMutex := selectorExpr(fake, method.mutexName())
+		body.List = append(body.List,
+			exprStmt(call(selectorExpr(fakeMethodMutex, "Lock"))),
+			assign(selectorExpr(fake, method.recordName()), call(ast.NewIdent("make"), ifce.methodMap(method))),
+			assign(selectorExpr(fake, method.callsName()), &ast.BasicLit{Kind: token.INT, Value: "0"}),
+			method.countCalls(),
+			exprStmt(call(selectorExpr(fakeMethodMutex, "Unlock"))),
+		)
+	}
+	body.List = append(body.List, exprStmt(call(
+		selectorExpr(selectorExpr(selectorExpr(fake, "opts"), "Recorder"), "Reset"), fake,
+	)))
+
+	recv := ifce.recv()
+
+	return funcDecl(recv, ifce.helperName("ResetCalls"), fieldList(), fieldList(), body)
+}
+
+func (ifce Interface) generateSnapshotStruct() ast.Decl {
+	fieldList := []*ast.Field{}
+	for _, method := range ifce.Methods {
+		fieldList = append(fieldList,
+			field(ifce.methodMap(method), method.fieldName()),
+			field(ifce.methodMap(method), method.recordName()),
+			field(&ast.ArrayType{Elt: ifce.typeRef(method.whenStructName(ifce.fakeName()))}, method.whenFieldName()),
+		)
+		if _, ok := method.errorResult(); ok {
+			fieldList = append(fieldList,
+				field(selectorExpr(ast.NewIdent("tablemock"), "Every"), method.failsName()),
+				field(selectorExpr(ast.NewIdent("tablemock"), "Failures"), method.failuresName()),
+			)
+		}
+		fieldList = append(fieldList,
+			field(selectorExpr(ast.NewIdent("tablemock"), "Panics"), method.panicsName()),
+			field(durationType(), method.delayName()),
+			field(selectorExpr(ast.NewIdent("tablemock"), "Delays"), method.delaysName()),
+			field(selectorExpr(ast.NewIdent("tablemock"), "Sequence"), method.sequenceName()),
+		)
+		if method.setsArgs(ifce) {
+			fieldList = append(fieldList, field(selectorExpr(ast.NewIdent("tablemock"), "Sets"), method.setsName()))
+		}
+		fieldList = append(fieldList,
+			field(ast.NewIdent("int"), lowerFirst(method.callsName())),
+		)
+	}
+	fieldList = append(fieldList, field(&ast.ArrayType{Elt: selectorExpr(ast.NewIdent("tablemock"), "Call")}, "calls"))
+
+	return generateStruct(ifce.snapshotName(), ifce.typeParams(), fieldList)
+}
+
+func (ifce Interface) generateSnapshot() *ast.FuncDecl {
+	fake := ast.NewIdent("fake")
+	snapshot := ast.NewIdent("snapshot")
+
+	body := blockStmt(&ast.AssignStmt{
+		Lhs: expression(snapshot),
+		Rhs: expression(&ast.CompositeLit{
+			Type: ifce.typeRef(ifce.snapshotName()),
+			Elts: expression(&ast.KeyValueExpr{
+				Key:   ast.NewIdent("calls"),
+				Value: call(selectorExpr(fake, ifce.helperName("Calls"))),
+			}),
+		}),
+		Tok: token.DEFINE,
+	})
+	for _, method := range ifce.Methods {
+		fakeMethodMutex := selectorExpr(fake, method.mutexName())
+		body.List = append(body.List, exprStmt(call(selectorExpr(fakeMethodMutex, "RLock"))))
+		body.List = append(body.List, method.copyState(ifce, snapshot, fake, lowerFirst(method.callsName()), method.callsName())...)
+		body.List = append(body.List, exprStmt(call(selectorExpr(fakeMethodMutex, "RUnlock"))))
+	}
+	body.List = append(body.List, &ast.ReturnStmt{Results: expression(snapshot)})
+
+	recv := ifce.recv()
+	results := fieldList(field(ifce.typeRef(ifce.snapshotName())))
+
+	return funcDecl(recv, ifce.helperName("Snapshot"), fieldList(), results, body)
+}
+
+func (ifce Interface) generateRestore() *ast.FuncDecl {
+	fake := ast.NewIdent("fake")
+	snapshot := ast.NewIdent("snapshot")
+
+	body := blockStmt()
+	for _, method := range ifce.Methods {
+		fakeMethodMutex := selectorExpr(fake, method.mutexName())
+		body.List = append(body.List, exprStmt(call(selectorExpr(fakeMethodMutex, "Lock"))))
+		body.List = append(body.List, method.copyState(ifce, fake, snapshot, method.callsName(), lowerFirst(method.callsName()))...)
+		body.List = append(body.List, method.countCalls(), exprStmt(call(selectorExpr(fakeMethodMutex, "Unlock"))))
+	}
+	body.List = append(body.List, exprStmt(&ast.CallExpr{
+		Fun:      selectorExpr(selectorExpr(selectorExpr(fake, "opts"), "Recorder"), "Reset"),
+		Args:     expression(fake, selectorExpr(snapshot, "calls")),
+		Ellipsis: 1,
+	}))
+
+	recv := ifce.recv()
+	params := fieldList(field(ifce.typeRef(ifce.snapshotName()), "snapshot"))
+
+	return funcDecl(recv, ifce.helperName("Restore"), params, fieldList(), body)
+}
+
+// copyState copies a method's programmed returns, recorded calls, rules,
+// injected failures and panics, delays, sequence, arg sets and call count from src to dst.
+// The maps and rules are copied so that neither side sees later changes made
+// to the other.
+func (meth Method) copyState(ifce Interface, dst, src ast.Expr, dstCalls, srcCalls string) []ast.Stmt {
+	stmts := copyMap(selectorExpr(dst, meth.fieldName()), selectorExpr(src, meth.fieldName()), ifce.methodMap(meth))
+	stmts = append(stmts, copyMap(selectorExpr(dst, meth.recordName()), selectorExpr(src, meth.recordName()), ifce.methodMap(meth))...)
+
+	whenType := &ast.ArrayType{Elt: ifce.typeRef(meth.whenStructName(ifce.fakeName()))}
+	stmts = append(stmts, assign(selectorExpr(dst, meth.whenFieldName()), &ast.CallExpr{
+		Fun:      ast.NewIdent("append"),
+		Args:     expression(call(whenType, ast.NewIdent("nil")), selectorExpr(src, meth.whenFieldName())),
+		Ellipsis: 1,
+	}))
+	if _, ok := meth.errorResult(); ok {
+		stmts = append(stmts,
+			assign(selectorExpr(dst, meth.failsName()), selectorExpr(src, meth.failsName())),
+			assign(selectorExpr(dst, meth.failuresName()), selectorExpr(src, meth.failuresName())),
+		)
+	}
+	stmts = append(stmts,
+		assign(selectorExpr(dst, meth.panicsName()), selectorExpr(src, meth.panicsName())),
+		assign(selectorExpr(dst, meth.delayName()), selectorExpr(src, meth.delayName())),
+		assign(selectorExpr(dst, meth.delaysName()), selectorExpr(src, meth.delaysName())),
+		assign(selectorExpr(dst, meth.sequenceName()), selectorExpr(src, meth.sequenceName())),
+	)
+	if meth.setsArgs(ifce) {
+		stmts = append(stmts, assign(selectorExpr(dst, meth.setsName()), selectorExpr(src, meth.setsName())))
+	}
+	return append(stmts, assign(selectorExpr(dst, dstCalls), selectorExpr(src, srcCalls)))
+}
+
+func copyMap(dst, src ast.Expr, mapType *ast.MapType) []ast.Stmt {
+	return []ast.Stmt{
+		assign(dst, call(ast.NewIdent("make"), mapType, call(ast.NewIdent("len"), src))),
+		&ast.RangeStmt{
+			Key: ast.NewIdent("call"), Value: ast.NewIdent("fakeMethod"),
+			Tok:  token.DEFINE,
+			X:    src,
+			Body: blockStmt(assign(&ast.IndexExpr{X: dst, Index: ast.NewIdent("call")}, ast.NewIdent("fakeMethod"))),
+		},
+	}
+}
@@ -0,0 +1,98 @@
+package mock
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+func (method Method) sequenceName() string {
+	return toMethodName(method.Name, "Sequence")
+}
+
+// sequenceReturns returns the statement giving a call past the end of a
+// sequence the returns picked by the sequence's end, unless the call was
+// programmed on its own. It has to run while holding the method's lock.
+func (meth Method) sequenceReturns(fakeMethod, configured ast.Expr) ast.Stmt {
+	fake := ast.NewIdent("fake")
+	index := call(selectorExpr(selectorExpr(fake, meth.sequenceName()), "Index"), selectorExpr(fake, meth.callsName()))
+
+	return &ast.IfStmt{
+		Cond: &ast.UnaryExpr{Op: token.NOT, X: configured},
+		Body: blockStmt(&ast.AssignStmt{
+			Lhs: expression(fakeMethod, configured),
+			Tok: token.ASSIGN,
+			Rhs: expression(&ast.IndexExpr{X: selectorExpr(fake, meth.fieldName()), Index: index}),
+		}),
+	}
+}
+
+// generateSequence returns RunReturnsSequence, RunSequenceEnd and
+// RunForCallRange. RunReturnsSequence programs the first calls the way
+// RunForCall does, after dropping what an earlier sequence programmed.
+func (meth Method) generateSequence(ifce Interface) []ast.Decl {
+	fake := ast.NewIdent("fake")
+	fakeMethodMutex := selectorExpr(fake, meth.mutexName())
+	sequence := selectorExpr(fake, meth.sequenceName())
+	fakeMethods := ast.NewIdent("fakeMethods")
+	callIdent := ast.NewIdent("call")
+	recv := ifce.recv()
+	results := fieldList(field(ifce.fakeType()))
+
+	returnsSequence := funcDecl(recv, strings.Title(meth.Name)+"ReturnsSequence",
+		fieldList(field(&ast.Ellipsis{Elt: ifce.typeRef(meth.structName(ifce.fakeName()))}, "fakeMethods")),
+		results,
+		blockStmt(
+			exprStmt(call(selectorExpr(fakeMethodMutex, "Lock"))),
+			&ast.ForStmt{
+				Init: &ast.AssignStmt{Lhs: expression(callIdent), Tok: token.DEFINE, Rhs: expression(&ast.BasicLit{Kind: token.INT, Value: "0"})},
+				Cond: &ast.BinaryExpr{X: callIdent, Op: token.LSS, Y: selectorExpr(sequence, "Len")},
+				Post: &ast.IncDecStmt{X: callIdent, Tok: token.INC},
+				Body: blockStmt(exprStmt(call(ast.NewIdent("delete"), selectorExpr(fake, meth.fieldName()), callIdent))),
+			},
+			&ast.RangeStmt{
+				Key: ast.NewIdent("call"), Value: ast.NewIdent("fakeMethod"),
+				Tok:  token.DEFINE,
+				X:    fakeMethods,
+				Body: blockStmt(meth.assignToMap("call", ast.NewIdent("fakeMethod"))),
+			},
+			assign(selectorExpr(sequence, "Len"), call(ast.NewIdent("len"), fakeMethods)),
+			exprStmt(call(selectorExpr(fakeMethodMutex, "Unlock"))),
+			&ast.ReturnStmt{Results: expression(fake)},
+		),
+	)
+
+	sequenceEnd := funcDecl(recv, strings.Title(meth.Name)+"SequenceEnd",
+		fieldList(field(selectorExpr(ast.NewIdent("tablemock"), "SequenceEnd"), "end")),
+		results,
+		blockStmt(
+			exprStmt(call(selectorExpr(fakeMethodMutex, "Lock"))),
+			assign(selectorExpr(sequence, "End"), ast.NewIdent("end")),
+			exprStmt(call(selectorExpr(fakeMethodMutex, "Unlock"))),
+			&ast.ReturnStmt{Results: expression(fake)},
+		),
+	)
+
+	forCallRange := funcDecl(recv, strings.Title(meth.Name)+"ForCallRange",
+		fieldList(
+			field(ast.NewIdent("int"), "from", "to"),
+			field(&ast.Ellipsis{Elt: ifce.typeRef(meth.funcName(ifce.fakeName()))}, "fns"),
+		),
+		results,
+		blockStmt(
+			&ast.ForStmt{
+				Init: &ast.AssignStmt{Lhs: expression(callIdent), Tok: token.DEFINE, Rhs: expression(ast.NewIdent("from"))},
+				Cond: &ast.BinaryExpr{X: callIdent, Op: token.LSS, Y: ast.NewIdent("to")},
+				Post: &ast.IncDecStmt{X: callIdent, Tok: token.INC},
+				Body: blockStmt(exprStmt(&ast.CallExpr{
+					Fun:      selectorExpr(fake, strings.Title(meth.Name)+"ForCall"),
+					Args:     expression(callIdent, ast.NewIdent("fns")),
+					Ellipsis: 1,
+				})),
+			},
+			&ast.ReturnStmt{Results: expression(fake)},
+		),
+	)
+
+	return []ast.Decl{returnsSequence, sequenceEnd, forCallRange}
+}
@@ -0,0 +1,116 @@
+package mock
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+func (method Method) setsName() string {
+	return toMethodName(method.Name, "Sets")
+}
+
+// setsArgs reports whether SetsArg is generated for meth, which it is when
+// one of its args can be written through.
+func (meth Method) setsArgs(ifce Interface) bool {
+	for _, arg := range meth.Args {
+		if writable(arg.Type, ifce.TypeParams) {
+			return true
+		}
+	}
+	return false
+}
+
+// writable reports whether an arg of type typ may point to memory SetsArg can
+// write into: a pointer, or an empty interface or type parameter that may
+// hold one, or variadic args of those.
+func writable(typ ast.Expr, typeParams []Value) bool {
+	switch typ := typ.(type) {
+	case *ast.StarExpr:
+		return true
+	case *ast.Ellipsis:
+		return writable(typ.Elt, typeParams)
+	case *ast.InterfaceType:
+		return typ.Methods.NumFields() == 0
+	case *ast.Ident:
+		if typ.Name == "interface{}" || typ.Name == "any" {
+			return true
+		}
+		for _, param := range typeParams {
+			if param.Name == typ.Name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// takeSets returns the statement keeping the values set by SetsArg for the
+// call. It has to run while holding the method's lock; since a Sets never
+// changes, it can be applied once the lock is released.
+func (meth Method) takeSets() ast.Stmt {
+	return &ast.AssignStmt{
+		Lhs: expression(ast.NewIdent("fakeSets")),
+		Tok: token.DEFINE,
+		Rhs: expression(selectorExpr(ast.NewIdent("fake"), meth.setsName())),
+	}
+}
+
+// applySets returns the statement copying the values set by SetsArg into
+// the memory the call's pointer args point to.
+func (meth Method) applySets() ast.Stmt {
+	apply := "Apply"
+	args := expression()
+	for _, arg := range meth.Args {
+		args = append(args, ast.NewIdent(arg.argName()))
+		if _, ok := arg.Type.(*ast.Ellipsis); ok {
+			apply = "ApplyVariadic"
+		}
+	}
+	return exprStmt(call(selectorExpr(ast.NewIdent("fakeSets"), apply), args...))
+}
+
+// generateSets returns RunSetsArg, along with a typed setter, e.g.
+// RunSetsOut, for every arg of a pointer type. The setter of an arg named arg
+// is RunSetsArgArg so as not to clash with RunSetsArg.
+func (meth Method) generateSets(ifce Interface) []ast.Decl {
+	fake := ast.NewIdent("fake")
+	fakeMethodMutex := selectorExpr(fake, meth.mutexName())
+	sets := selectorExpr(fake, meth.setsName())
+	recv := ifce.recv()
+	results := fieldList(field(ifce.fakeType()))
+	setsArg := strings.Title(meth.Name) + "SetsArg"
+
+	decls := []ast.Decl{funcDecl(recv, setsArg,
+		fieldList(field(ast.NewIdent("int"), "n"), field(ast.NewIdent("interface{}"), "value")),
+		results,
+		blockStmt(
+			exprStmt(call(selectorExpr(fakeMethodMutex, "Lock"))),
+			assign(sets, call(selectorExpr(sets, "With"), ast.NewIdent("n"), ast.NewIdent("value"))),
+			exprStmt(call(selectorExpr(fakeMethodMutex, "Unlock"))),
+			&ast.ReturnStmt{Results: expression(fake)},
+		),
+	)}
+	for i, arg := range meth.Args {
+		star, ok := arg.Type.(*ast.StarExpr)
+		if !ok {
+			continue
+		}
+		name := strings.Title(meth.Name) + "Sets" + arg.fieldName()
+		if name == setsArg {
+			name += "Arg"
+		}
+		decls = append(decls, funcDecl(recv, name,
+			fieldList(field(star.X, "value")),
+			results,
+			blockStmt(&ast.ReturnStmt{Results: expression(call(
+				selectorExpr(fake, setsArg),
+				&ast.BasicLit{Kind: token.INT, Value: strconv.Itoa(i)},
+				ast.NewIdent("value"),
+			))}),
+		))
+	}
+
+	return decls
+}
@@ -0,0 +1,105 @@
+package mock
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// spies reports whether the fake can wrap a real implementation of the
+// interface, which takes knowing the package the interface was read from.
+func (ifce Interface) spies() bool {
+	return ifce.PkgPath != ""
+}
+
+// spyField returns the field holding the implementation a spy forwards to.
+func (ifce Interface) spyField(pkg string) *ast.Field {
+	return field(instantiate(ifce.sourceType(pkg), ifce.TypeParams), "real")
+}
+
+// generateSpyConstructor returns NewRunnerSpy, which creates a fake that
+// forwards every call nothing was programmed for to real.
+func (ifce Interface) generateSpyConstructor(pkg string) *ast.FuncDecl {
+	fake := ast.NewIdent("fake")
+	real := ast.NewIdent("real")
+	opts := ast.NewIdent("opts")
+
+	body := blockStmt(
+		&ast.AssignStmt{
+			Lhs: expression(fake),
+			Tok: token.DEFINE,
+			Rhs: expression(&ast.CallExpr{
+				Fun:      ifce.typeRef(ifce.constructorName()),
+				Args:     expression(opts),
+				Ellipsis: 1,
+			}),
+		},
+		assign(selectorExpr(fake, "real"), real),
+		&ast.ReturnStmt{Results: expression(fake)},
+	)
+
+	funcName := ifce.constructorName() + "Spy"
+	params := fieldList(
+		ifce.spyField(pkg),
+		field(&ast.Ellipsis{Elt: selectorExpr(ast.NewIdent("tablemock"), "Option")}, "opts"),
+	)
+	results := fieldList(field(ifce.fakeType()))
+
+	decl := funcDecl(nil, funcName, params, results, body)
+	decl.Type.TypeParams = ifce.typeParams()
+
+	return decl
+}
+
+// forward returns the statement that calls the real implementation of a spy
+// when no returns were programmed for the call, keeping what it returned, with
+// any failure injected for the call put over it, in the call's record. Only a
+// fake that isn't spying reports the call as unexpected.
+// The real implementation of a named func type is called as is. Like
+// unexpected, it has to run after the method's lock is released.
+func (meth Method) forward(ifce Interface, configured ast.Expr) ast.Stmt {
+	fake := ast.NewIdent("fake")
+	fakeMethod := ast.NewIdent("fakeMethod")
+	fakeMethodMutex := selectorExpr(fake, meth.mutexName())
+	real := selectorExpr(fake, "real")
+
+	realCall := &ast.CallExpr{Fun: selectorExpr(real, meth.Name)}
+	if ifce.Func {
+		realCall.Fun = real
+	}
+	for _, arg := range meth.Args {
+		realCall.Args = append(realCall.Args, ast.NewIdent(arg.argName()))
+		if _, ok := arg.Type.(*ast.Ellipsis); ok {
+			realCall.Ellipsis = 1
+		}
+	}
+
+	body := blockStmt(exprStmt(realCall))
+	if len(meth.Rets) > 0 {
+		results := expression()
+		for _, ret := range meth.Rets {
+			results = append(results, selectorExpr(fakeMethod, ret.fieldName()))
+		}
+		body.List = []ast.Stmt{&ast.AssignStmt{Lhs: results, Tok: token.ASSIGN, Rhs: expression(realCall)}}
+		if ret, ok := meth.errorResult(); ok {
+			body.List = append(body.List, meth.applyFailure(fakeMethod, ret))
+		}
+		body.List = append(body.List,
+			exprStmt(call(selectorExpr(fakeMethodMutex, "Lock"))),
+			assign(&ast.IndexExpr{
+				X:     selectorExpr(fake, meth.recordName()),
+				Index: selectorExpr(ast.NewIdent("fakeCall"), "Index"),
+			}, fakeMethod),
+			exprStmt(call(selectorExpr(fakeMethodMutex, "Unlock"))),
+		)
+	}
+
+	return &ast.IfStmt{
+		Cond: &ast.BinaryExpr{
+			X:  &ast.UnaryExpr{Op: token.NOT, X: configured},
+			Op: token.LAND,
+			Y:  &ast.BinaryExpr{X: real, Op: token.NEQ, Y: ast.NewIdent("nil")},
+		},
+		Body: body,
+		Else: unexpected(meth.unprogrammed(configured)),
+	}
+}
@@ -0,0 +1,193 @@
+package mock
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"io"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// DefaultTemplate renders the table-mocks fake built by ToFile, which is
+// built as an AST as its features share state and layout. Custom templates are
+// executed with the same TemplateData and helpers; templates/stub.tmpl is the
+// one to start from.
+const DefaultTemplate = `{{ tableMock . }}`
+
+// TemplateData is what a fake template is executed with. Package is the name
+// of the package the fake is generated into, and Interface.Package the one it
+// was read from.
+type TemplateData struct {
+	Interface Interface
+	Package   string
+}
+
+// TemplateFuncs returns the helpers available to fake templates:
+//
+//	typeString    the Go source of a type, such as .Type of a Value
+//	fieldType     the type of a Value as a struct field, with ...T as []T
+//	fieldName     the exported struct field name of a Value
+//	params        "name type" pairs of a []Value, as in a signature
+//	args          the names of a []Value, as passed on in a call
+//	results       the result list of a []Value, parenthesized when needed
+//	typeParams    the [K comparable, V any] list of a generic Interface
+//	typeArgs      the [K, V] list of a generic Interface
+//	imports       the import paths the source types of a TemplateData need
+//	qualified     the interface name of a TemplateData, qualified if needed
+//	title         the name with its first letter upper cased
+//	lowerFirst    the name with its first letter lower cased
+//	tableMock     the complete table-mocks fake for a TemplateData
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"typeString": typeString,
+		"fieldType":  func(val Value) (string, error) { return typeString(resolveAssignType(val.Type)) },
+		"fieldName":  Value.fieldName,
+		"params":     params,
+		"args":       args,
+		"results":    results,
+		"typeParams": func(ifce Interface) (string, error) { return typeList(ifce.TypeParams, true) },
+		"typeArgs":   func(ifce Interface) (string, error) { return typeList(ifce.TypeParams, false) },
+		"imports":    TemplateData.imports,
+		"qualified":  TemplateData.qualified,
+		"title":      strings.Title,
+		"lowerFirst": lowerFirst,
+		"tableMock":  func(data TemplateData) (string, error) { return printFile(data.Interface.ToFile(data.Package)) },
+	}
+}
+
+// ParseTemplate parses text as a fake template with TemplateFuncs.
+func ParseTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(TemplateFuncs()).Parse(text)
+}
+
+var defaultTemplate = template.Must(ParseTemplate("table-mocks", DefaultTemplate))
+
+// GenerateTemplate executes tmpl for ifce and writes the result, formatted
+// with format.Source, to w. DefaultTemplate prints a formatted fake already,
+// so its result is written as is. It fails when the fake uses unexported types
+// of a package other than pkg.
+func GenerateTemplate(tmpl *template.Template, ifce *Interface, pkg string, w io.Writer) error {
+	if err := ifce.checkPackage(pkg); err != nil {
+		return err
+	}
+
+	buf := new(bytes.Buffer)
+	if err := tmpl.Execute(buf, TemplateData{Interface: ifce.inPackage(pkg), Package: pkg}); err != nil {
+		return err
+	}
+
+	src := buf.Bytes()
+	if tmpl != defaultTemplate {
+		var err error
+		if src, err = format.Source(src); err != nil {
+			return fmt.Errorf("formatting the fake for %s: %v", ifce.Name, err)
+		}
+	}
+
+	_, err := w.Write(src)
+	return err
+}
+
+// typeString returns the Go source of expr. It fails on an expression
+// format.Node can't print, so that a template running into one stops with an
+// error.
+func typeString(expr ast.Expr) (string, error) {
+	buf := new(strings.Builder)
+	if err := format.Node(buf, token.NewFileSet(), expr); err != nil {
+		return "", fmt.Errorf("printing type %T: %v", expr, err)
+	}
+	return buf.String(), nil
+}
+
+func params(vals []Value) (string, error) {
+	list := []string{}
+	for _, val := range vals {
+		typ, err := typeString(val.Type)
+		if err != nil {
+			return "", err
+		}
+		list = append(list, val.Name+" "+typ)
+	}
+	return strings.Join(list, ", "), nil
+}
+
+func args(vals []Value) string {
+	list := []string{}
+	for _, val := range vals {
+		arg := val.argName()
+		if _, ok := val.Type.(*ast.Ellipsis); ok {
+			arg += "..."
+		}
+		list = append(list, arg)
+	}
+	return strings.Join(list, ", ")
+}
+
+func results(vals []Value) (string, error) {
+	switch len(vals) {
+	case 0:
+		return "", nil
+	case 1:
+		if vals[0].Name == "" {
+			return typeString(vals[0].Type)
+		}
+	}
+	list, err := params(vals)
+	if err != nil {
+		return "", err
+	}
+	return "(" + list + ")", nil
+}
+
+func typeList(vals []Value, constraints bool) (string, error) {
+	if len(vals) == 0 {
+		return "", nil
+	}
+
+	list := []string{}
+	for _, val := range vals {
+		if !constraints {
+			list = append(list, val.Name)
+			continue
+		}
+		typ, err := typeString(val.Type)
+		if err != nil {
+			return "", err
+		}
+		list = append(list, val.Name+" "+typ)
+	}
+	return "[" + strings.Join(list, ", ") + "]", nil
+}
+
+func (data TemplateData) imports() []string {
+	seen := make(map[string]bool)
+	imports := []string{}
+	for _, imp := range data.Interface.Imports {
+		if !seen[imp] {
+			seen[imp] = true
+			imports = append(imports, imp)
+		}
+	}
+	if data.Interface.PkgPath != "" && !data.Interface.samePackage(data.Package) && !seen[data.Interface.PkgPath] {
+		imports = append(imports, data.Interface.PkgPath)
+	}
+	sort.Strings(imports)
+
+	return imports
+}
+
+func (data TemplateData) qualified() (string, error) {
+	typeArgs, err := typeList(data.Interface.TypeParams, false)
+	if err != nil {
+		return "", err
+	}
+	name := data.Interface.Name + typeArgs
+	if data.Interface.PkgPath == "" || data.Interface.samePackage(data.Package) {
+		return name, nil
+	}
+	return data.Interface.Package + "." + name, nil
+}
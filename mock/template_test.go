@@ -0,0 +1,334 @@
+package mock_test
+
+import (
+	"bytes"
+	"go/ast"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+
+	. "github.com/vitreuz/table-mocks/mock"
+)
+
+const stubTemplate = `package {{ .Package }}
+
+import (
+{{- range imports . }}
+	"{{ . }}"
+{{- end }}
+)
+{{ if not .Interface.TypeParams }}
+var _ {{ qualified . }} = (*{{ .Interface.Name }}Stub)(nil)
+{{ end }}
+type {{ .Interface.Name }}Stub{{ typeParams .Interface }} struct {
+{{- range .Interface.Methods }}
+	{{ .Name }}Stub func({{ params .Args }}) {{ results .Rets }}
+{{- end }}
+}
+{{ range .Interface.Methods }}
+func (stub *{{ $.Interface.Name }}Stub{{ typeArgs $.Interface }}) {{ .Name }}({{ params .Args }}) {{ results .Rets }} {
+	{{ if .Rets }}return {{ end }}stub.{{ .Name }}Stub({{ args .Args }})
+}
+{{ end }}`
+
+func TestGenerateTemplate(t *testing.T) {
+	check := func(fns ...checkReader) []checkReader { return fns }
+
+	tests := [...]struct {
+		name   string
+		tmpl   string
+		ifce   *Interface
+		pkg    string
+		checks []checkReader
+	}{
+		{
+			"Custom template",
+			stubTemplate,
+			newTestInterface("Logger").
+				WithSource("design", "example.com/design").
+				WithImport("time").
+				WithMethod(newTestMethod("Log").
+					WithArg(newTestValue("format")).
+					WithArg(newTestValue("args").asEllipse())).
+				WithMethod(newTestMethod("Since").
+					WithArg(newTestValue("start")).
+					WithRet(newTestValue("").asDuration())).
+				ToInterface(),
+			"fake",
+			check(expectReader(strings.NewReader(`
+package fake
+
+import (
+	"example.com/design"
+	"time"
+)
+
+var _ design.Logger = (*LoggerStub)(nil)
+
+type LoggerStub struct {
+	LogStub   func(format string, args ...string)
+	SinceStub func(start string) time.Duration
+}
+
+func (stub *LoggerStub) Log(format string, args ...string) {
+	stub.LogStub(format, args...)
+}
+
+func (stub *LoggerStub) Since(start string) time.Duration {
+	return stub.SinceStub(start)
+}
+`,
+			))),
+		}, {
+			"Generic interface",
+			stubTemplate,
+			&Interface{
+				Name:       "Store",
+				Package:    "design",
+				PkgPath:    "example.com/design",
+				TypeParams: []Value{{Name: "K", Type: ast.NewIdent("comparable")}, {Name: "V", Type: ast.NewIdent("any")}},
+				Methods: []Method{{
+					Name: "Get",
+					Args: []Value{{Name: "key", Type: ast.NewIdent("K")}},
+					Rets: []Value{{Name: "value", Type: ast.NewIdent("V")}, {Name: "ok", Type: ast.NewIdent("bool")}},
+				}},
+			},
+			"fake",
+			check(expectReader(strings.NewReader(`
+package fake
+
+import (
+	"example.com/design"
+)
+
+type StoreStub[K comparable, V any] struct {
+	GetStub func(key K) (value V, ok bool)
+}
+
+func (stub *StoreStub[K, V]) Get(key K) (value V, ok bool) {
+	return stub.GetStub(key)
+}
+`,
+			))),
+		}, {
+			"Same package",
+			stubTemplate,
+			&Interface{
+				Name:    "Runner",
+				Package: "design",
+				PkgPath: "example.com/design",
+				Imports: []string{"example.com/design", "time"},
+				Methods: []Method{{
+					Name: "Run",
+					Args: []Value{
+						{Name: "id", Type: &ast.SelectorExpr{X: ast.NewIdent("design"), Sel: ast.NewIdent("ID")}},
+						{Name: "every", Type: &ast.SelectorExpr{X: ast.NewIdent("time"), Sel: ast.NewIdent("Duration")}},
+					},
+					Rets: []Value{
+						{Name: "res", Type: &ast.StarExpr{X: &ast.SelectorExpr{X: ast.NewIdent("design"), Sel: ast.NewIdent("result")}}},
+						{Name: "err", Type: ast.NewIdent("error")},
+					},
+				}},
+				Unexported: []string{"result"},
+			},
+			"design",
+			check(expectReader(strings.NewReader(`
+package design
+
+import (
+	"time"
+)
+
+var _ Runner = (*RunnerStub)(nil)
+
+type RunnerStub struct {
+	RunStub func(id ID, every time.Duration) (res *result, err error)
+}
+
+func (stub *RunnerStub) Run(id ID, every time.Duration) (res *result, err error) {
+	return stub.RunStub(id, every)
+}
+`,
+			))),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := ParseTemplate(tt.name, tt.tmpl)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			output := new(strings.Builder)
+			if err := GenerateTemplate(tmpl, tt.ifce, tt.pkg, output); err != nil {
+				t.Fatal(err)
+			}
+			for _, check := range tt.checks {
+				for _, checkErr := range check(strings.NewReader(output.String())) {
+					if checkErr != nil {
+						t.Error(checkErr)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateTemplateFormatError(t *testing.T) {
+	tmpl, err := ParseTemplate("broken", `package {{ .Package }} func {`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = GenerateTemplate(tmpl, newTestInterface("Runner").ToInterface(), "fake", new(strings.Builder))
+	if err == nil || !strings.Contains(err.Error(), "Runner") {
+		t.Errorf("expected a formatting error for Runner but got %v", err)
+	}
+}
+
+func TestGenerateTemplateTypeError(t *testing.T) {
+	tmpl, err := ParseTemplate("stub", stubTemplate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ifce := &Interface{Name: "Runner", Methods: []Method{{Name: "Run", Args: []Value{{Name: "id"}}}}}
+	err = GenerateTemplate(tmpl, ifce, "fake", new(strings.Builder))
+	if err == nil || !strings.Contains(err.Error(), "printing type") {
+		t.Errorf("expected an error printing the type of id but got %v", err)
+	}
+}
+
+func TestGenerateTemplateTableMockError(t *testing.T) {
+	ifce := &Interface{Name: "Runner", Methods: []Method{{Name: "Run", Args: []Value{{Name: "id"}}}}}
+	err := GenerateTemplate(defaultTemplate(t), ifce, "fake", new(strings.Builder))
+	if err == nil || !strings.Contains(err.Error(), "tableMock") {
+		t.Errorf("expected an error printing the fake from tableMock but got %v", err)
+	}
+}
+
+func TestGenerateTemplateUnexported(t *testing.T) {
+	runner := newTestInterface("Runner").WithSource("design", "example.com/design").ToInterface()
+	runner.Unexported = []string{"options", "result"}
+
+	tests := [...]struct {
+		name   string
+		ifce   *Interface
+		expect string
+	}{
+		{
+			"Unexported types",
+			runner,
+			"the fake for Runner uses the unexported types options, result of package design, so it can only be generated into that package",
+		}, {
+			"Unexported interface",
+			newTestInterface("runner").WithSource("design", "example.com/design").ToInterface(),
+			"runner is unexported, so its fake can only be generated into package design",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := GenerateTemplate(defaultTemplate(t), tt.ifce, "fake", new(strings.Builder))
+			if err == nil || err.Error() != tt.expect {
+				t.Errorf("expected error %q but got %v", tt.expect, err)
+			}
+			if err := GenerateTemplate(defaultTemplate(t), tt.ifce, "design", new(strings.Builder)); err != nil {
+				t.Errorf("expected the fake to be generated into design but got %v", err)
+			}
+		})
+	}
+}
+
+// TestExampleTemplate renders the interfaces of the corpus with the stub
+// template shipped in templates and type checks the results.
+func TestExampleTemplate(t *testing.T) {
+	text, err := ioutil.ReadFile("../templates/stub.tmpl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := ParseTemplate("stub.tmpl", string(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo, err := filepath.Abs("..")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, dir := range corpus(t) {
+		dir := dir
+		t.Run(name, func(t *testing.T) {
+			modPath := corpusPath + "/" + name
+			module := tempModule(t, modPath, dir)
+
+			mock, err := ReadPkg(module, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.MkdirAll(filepath.Join(module, "fake"), 0755); err != nil {
+				t.Fatal(err)
+			}
+			for _, ifce := range mock.Interfaces {
+				ifce := ifce
+				pkg := "fake"
+				if ifce.Local() {
+					pkg = ifce.Package
+				}
+				buf := new(bytes.Buffer)
+				if err := GenerateTemplate(tmpl, &ifce, pkg, buf); err != nil {
+					t.Fatalf("rendering %s: %s", ifce.Name, err)
+				}
+				if err := ioutil.WriteFile(fakeFile(module, ifce), buf.Bytes(), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			imp := newDirImporter(token.NewFileSet(), map[string]string{
+				repoPath: repo,
+				modPath:  module,
+			})
+			if _, err := imp.Import(modPath); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := imp.Import(modPath + "/fake"); err != nil && !allLocal(mock.Interfaces) {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestDefaultTemplate(t *testing.T) {
+	ifce := newTestInterface("Runner").
+		WithMethod(newTestMethod("Run").WithArg(newTestValue("distance"))).
+		ToInterface()
+
+	tmpl, err := ParseTemplate("table-mocks", DefaultTemplate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	output := new(strings.Builder)
+	if err := GenerateTemplate(tmpl, ifce, "fake", output); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := GenerateHeader(ifce, "fake")
+	if !strings.Contains(output.String(), strings.TrimSpace(expected)) {
+		t.Errorf("expected the default template to render the table-mocks fake but got:\n%s", output)
+	}
+}
+
+func defaultTemplate(t *testing.T) *template.Template {
+	t.Helper()
+
+	tmpl, err := ParseTemplate("table-mocks", DefaultTemplate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tmpl
+}
@@ -0,0 +1,14 @@
+package aliases
+
+import "time"
+
+type ID = string
+
+type Status int
+
+type Timeout = time.Duration
+
+type Tracker interface {
+	Track(id ID, status Status) (Timeout, error)
+	History(ids ...ID) map[ID][]Status
+}
@@ -0,0 +1,10 @@
+package chans
+
+type Event struct {
+	Topic string
+}
+
+type Stream interface {
+	Subscribe(topic string) (<-chan Event, error)
+	Publish(events chan<- Event, done chan struct{})
+}
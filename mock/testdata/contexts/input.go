@@ -0,0 +1,8 @@
+package contexts
+
+import "context"
+
+type Fetcher interface {
+	Fetch(ctx context.Context, url string) ([]byte, error)
+	Close() error
+}
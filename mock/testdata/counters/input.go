@@ -0,0 +1,12 @@
+package counters
+
+type Counter interface {
+	Add(name string, delta int)
+	Calls() int
+}
+
+// CounterSnapshot has its fake generated next to the one of Counter, which
+// names its snapshot type CounterSnapshotFake then.
+type CounterSnapshot interface {
+	Counts() map[string]int
+}
@@ -0,0 +1,10 @@
+package decoders
+
+type Decoder interface {
+	Decode(v interface{}) error
+}
+
+type Rows interface {
+	Next() bool
+	Scan(dest ...interface{}) error
+}
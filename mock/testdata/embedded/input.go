@@ -0,0 +1,36 @@
+package embedded
+
+import (
+	"io"
+	"time"
+)
+
+type Book struct {
+	Title  string
+	Author string
+}
+
+type Shelf interface {
+	Add(book Book) error
+	Remove(title string) (Book, bool)
+}
+
+type Library interface {
+	Shelf
+
+	Lend(Book, time.Duration) (time.Time, error)
+	Search(query string, tags ...string) []Book
+	Export(w io.Writer) (int, int, error)
+	Close()
+}
+
+type Catalog interface {
+	Search(query string, tags ...string) []Book
+	Close()
+}
+
+// Archive embeds Library and Catalog, which overlap on Search and Close.
+type Archive interface {
+	Library
+	Catalog
+}
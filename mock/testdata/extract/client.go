@@ -0,0 +1,63 @@
+package store
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+type Object struct {
+	Key      string
+	Modified time.Time
+}
+
+type s3Client struct {
+	bucket string
+}
+
+func (c *s3Client) Get(ctx context.Context, key string) (io.ReadCloser, error) { return nil, nil }
+
+func (c *s3Client) Put(ctx context.Context, key string, body io.Reader) error { return nil }
+
+func (c s3Client) List(prefix string, limit ...int) ([]Object, error) { return nil, nil }
+
+func (c *s3Client) sign(key string) string { return key }
+
+type Cache[K comparable, V any] struct {
+	entries map[K]V
+}
+
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	value, ok := c.entries[key]
+	return value, ok
+}
+
+func (c *Cache[K, V]) Set(key K, value V) { c.entries[key] = value }
+
+type other struct{}
+
+func (other) Get() {}
+
+type base struct {
+	bucket string
+}
+
+func (b *base) Bucket() string { return b.bucket }
+
+func (b base) Close() error { return nil }
+
+func (b base) Get() {}
+
+// gcsClient gets Bucket and Close from base, but declares a Get of its own.
+type gcsClient struct {
+	*base
+	retries int
+}
+
+func (c *gcsClient) Get(key string) ([]byte, error) { return nil, nil }
+
+type lockedClient struct {
+	sync.Mutex
+	base
+}
@@ -0,0 +1,11 @@
+package funcs
+
+import "os"
+
+type Visit func(path string, info os.FileInfo) error
+
+type Walker interface {
+	Walk(root string, fn func(path string, info os.FileInfo) error) error
+	Visit(root string, visit Visit) error
+	Filter(func(string) bool) func(string) bool
+}
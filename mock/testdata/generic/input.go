@@ -0,0 +1,21 @@
+package generic
+
+type Store[K comparable, V any] interface {
+	Get(key K) (V, bool)
+	Put(key K, value V) error
+	Keys() []K
+}
+
+type Queue[T any] interface {
+	Push(items ...T)
+	Pop() (T, bool)
+}
+
+// Number is a constraint, which gets no fake.
+type Number interface {
+	~int | ~int64 | ~float64
+}
+
+type Summer[N Number] interface {
+	Sum(values ...N) N
+}
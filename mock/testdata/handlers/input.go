@@ -0,0 +1,15 @@
+package handlers
+
+import "context"
+
+type Request struct {
+	Path string
+}
+
+type HandlerFunc func(ctx context.Context, req Request) (string, error)
+
+type Middleware func(next HandlerFunc) HandlerFunc
+
+type Hook func(names ...string)
+
+type Mapper[T any] func(in T) (T, bool)
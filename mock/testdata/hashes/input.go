@@ -0,0 +1,8 @@
+package hashes
+
+type Hasher interface {
+	Write(p []byte) (n int, err error)
+	Sum(b []byte) []byte
+	Reset()
+	Size() int
+}
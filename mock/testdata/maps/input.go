@@ -0,0 +1,11 @@
+package maps
+
+type Entry struct {
+	Key   string
+	Value int
+}
+
+type Index interface {
+	Lookup(keys map[string]int) (map[string][]Entry, error)
+	Merge(map[string]int, map[string]*Entry)
+}
@@ -0,0 +1,9 @@
+package names
+
+import "context"
+
+type Finder interface {
+	Find(match string, configured int) (ok bool, err error)
+	Call(fakeCall int, tablemock string) (fakeMethod int)
+	Load(ctx context.Context, value *string, when, whenArg int) (delay, t int, fakeFailure error)
+}
@@ -0,0 +1,12 @@
+package pointers
+
+type User struct {
+	ID   int
+	Name string
+}
+
+type Repository interface {
+	Find(id int) (*User, error)
+	Save(*User) error
+	Load(id int, arg *User) error
+}
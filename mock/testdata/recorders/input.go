@@ -0,0 +1,13 @@
+package recorders
+
+import "time"
+
+type Recorder interface {
+	Record(name string, took time.Duration)
+	Flush() error
+}
+
+type Player interface {
+	LoadReplay(path string) error
+	Play() error
+}
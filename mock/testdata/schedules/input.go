@@ -0,0 +1,8 @@
+package schedules
+
+import "time"
+
+type Scheduler interface {
+	At(t time.Time, call func()) error
+	Cancel(calls int, tArg string) bool
+}
@@ -0,0 +1,7 @@
+package simple
+
+import "time"
+
+type Runner interface {
+	Run(distanceArg string) (durationResult time.Duration)
+}
@@ -0,0 +1,23 @@
+package unexported
+
+import "context"
+
+type record struct {
+	Key   string
+	Value []byte
+}
+
+type storer interface {
+	Get(ctx context.Context, key string) (record, error)
+	Put(ctx context.Context, r record) error
+}
+
+// Cache is exported, but its fake has to live in the package since record
+// isn't.
+type Cache interface {
+	Lookup(key string) (*record, bool)
+}
+
+type Clock interface {
+	Now() int64
+}
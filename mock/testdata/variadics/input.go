@@ -0,0 +1,6 @@
+package variadics
+
+type Logger interface {
+	Printf(format string, args ...interface{})
+	Log(...string)
+}
@@ -0,0 +1,119 @@
+package mock
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+const matchImport = "github.com/vitreuz/table-mocks/match"
+
+func (method Method) whenFieldName() string {
+	return toMethodName(method.Name, "When")
+}
+
+func (method Method) whenStructName(fakeName string) string {
+	return fakeName + strings.Title(method.Name) + "When"
+}
+
+// generateWhenStruct returns the rule built by XWhen. A rule keeps the
+// matchers for the call args and the results to return once they all match.
+func (meth Method) generateWhenStruct(ifce Interface) ast.Decl {
+	return generateStruct(meth.whenStructName(ifce.fakeName()), ifce.typeParams(), []*ast.Field{
+		ifce.recv(),
+		field(&ast.ArrayType{Elt: selectorExpr(ast.NewIdent("match"), "Matcher")}, "matchers"),
+		field(ifce.typeRef(meth.structName(ifce.fakeName())), "method"),
+	})
+}
+
+func (meth Method) generateWhen(ifce Interface) *ast.FuncDecl {
+	body := blockStmt(&ast.ReturnStmt{
+		Results: expression(&ast.UnaryExpr{
+			Op: token.AND,
+			X: &ast.CompositeLit{
+				Type: ifce.typeRef(meth.whenStructName(ifce.fakeName())),
+				Elts: expression(
+					&ast.KeyValueExpr{Key: ast.NewIdent("fake"), Value: ast.NewIdent("fake")},
+					&ast.KeyValueExpr{Key: ast.NewIdent("matchers"), Value: ast.NewIdent("matchers")},
+				),
+			},
+		}),
+	})
+
+	recv := ifce.recv()
+	funcName := strings.Title(meth.Name) + "When"
+	params := fieldList(field(&ast.Ellipsis{Elt: selectorExpr(ast.NewIdent("match"), "Matcher")}, "matchers"))
+	results := fieldList(field(&ast.StarExpr{X: ifce.typeRef(meth.whenStructName(ifce.fakeName()))}))
+
+	return funcDecl(recv, funcName, params, results, body)
+}
+
+func (meth Method) generateWhenReturns(ifce Interface) *ast.FuncDecl {
+	when := ast.NewIdent("when")
+	whenFake := selectorExpr(when, "fake")
+	whenMethod := selectorExpr(when, "method")
+	whenField := selectorExpr(whenFake, meth.whenFieldName())
+	fakeMethodMutex := selectorExpr(whenFake, meth.mutexName())
+
+	params := fieldList()
+	body := blockStmt()
+	for _, ret := range meth.Rets {
+		params.List = append(params.List, field(ret.Type, ret.argName()))
+		body.List = append(body.List, ret.assignToField(whenMethod))
+	}
+
+	body.List = append(body.List, []ast.Stmt{
+		exprStmt(call(selectorExpr(fakeMethodMutex, "Lock"))),
+		&ast.AssignStmt{
+			Lhs: expression(whenField),
+			Rhs: expression(call(ast.NewIdent("append"), whenField, &ast.StarExpr{X: when})),
+			Tok: token.ASSIGN,
+		},
+		exprStmt(call(selectorExpr(fakeMethodMutex, "Unlock"))),
+		&ast.ReturnStmt{Results: expression(whenFake)},
+	}...)
+
+	recv := field(&ast.StarExpr{X: ifce.typeRef(meth.whenStructName(ifce.fakeName()))}, "when")
+	results := fieldList(field(ifce.fakeType()))
+
+	return funcDecl(recv, "Returns", params, results, body)
+}
+
+// matchWhen returns the statement the interface method uses to consult the
+// XWhen rules. The first rule whose matchers accept the call args provides the
+// results and marks the call as configured, otherwise the per call results are
+// left untouched.
+func (meth Method) matchWhen(fakeMethod, configured ast.Expr) ast.Stmt {
+	when := ast.NewIdent("when")
+	args := expression(selectorExpr(when, "matchers"))
+	for _, arg := range meth.Args {
+		args = append(args, ast.NewIdent(arg.argName()))
+	}
+
+	matched := blockStmt()
+	for _, ret := range meth.Rets {
+		matched.List = append(matched.List, &ast.AssignStmt{
+			Lhs: expression(selectorExpr(fakeMethod, ret.fieldName())),
+			Rhs: expression(selectorExpr(selectorExpr(when, "method"), ret.fieldName())),
+			Tok: token.ASSIGN,
+		})
+	}
+	matched.List = append(matched.List, []ast.Stmt{
+		&ast.AssignStmt{
+			Lhs: expression(configured),
+			Rhs: expression(ast.NewIdent("true")),
+			Tok: token.ASSIGN,
+		},
+		&ast.BranchStmt{Tok: token.BREAK},
+	}...)
+
+	return &ast.RangeStmt{
+		Key: ast.NewIdent("_"), Value: when,
+		Tok: token.DEFINE,
+		X:   selectorExpr(ast.NewIdent("fake"), meth.whenFieldName()),
+		Body: blockStmt(&ast.IfStmt{
+			Cond: call(selectorExpr(ast.NewIdent("match"), "Args"), args...),
+			Body: matched,
+		}),
+	}
+}
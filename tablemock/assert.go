@@ -0,0 +1,82 @@
+// Package tablemock holds the runtime helpers that fakes generated by
+// table-mocks call into. Generated code stays small and declarative while the
+// reporting and bookkeeping logic lives here.
+package tablemock
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// AssertCalled fails the test if method was never called.
+func AssertCalled(t testing.TB, method string, calls int) {
+	t.Helper()
+
+	if calls == 0 {
+		t.Errorf("expected %s to be called, but it was not", method)
+	}
+}
+
+// AssertNotCalled fails the test if method was called at least once.
+func AssertNotCalled(t testing.TB, method string, calls int) {
+	t.Helper()
+
+	if calls != 0 {
+		t.Errorf("expected %s not to be called, but it was called %s", method, times(calls))
+	}
+}
+
+// AssertCalledTimes fails the test if method was not called exactly want
+// times.
+func AssertCalledTimes(t testing.TB, method string, want, calls int) {
+	t.Helper()
+
+	if calls != want {
+		t.Errorf("expected %s to be called %s, but it was called %s", method, times(want), times(calls))
+	}
+}
+
+// AssertCalledWith fails the test if the call at index call did not happen or
+// was made with arguments that differ from want. Arguments are compared with
+// reflect.DeepEqual and reported by name. Calls are counted from 0, so a
+// negative call fails the test as well.
+func AssertCalledWith(t testing.TB, method string, call, calls int, names []string, want, got []interface{}) {
+	t.Helper()
+
+	if call < 0 {
+		t.Errorf("expected %s to be called with args on call %d, but calls are counted from 0", method, call)
+		return
+	}
+	if call >= calls {
+		t.Errorf("expected %s to be called with args on call %d, but it was called %s", method, call, times(calls))
+		return
+	}
+
+	if diff := Diff(names, want, got); diff != "" {
+		t.Errorf("%s call %d args do not match (-expected +actual):\n%s", method, call, diff)
+	}
+}
+
+// Diff returns a readable, line per argument comparison of want and got, or an
+// empty string if every argument is deeply equal.
+func Diff(names []string, want, got []interface{}) string {
+	diff := new(strings.Builder)
+	for i, name := range names {
+		if reflect.DeepEqual(want[i], got[i]) {
+			continue
+		}
+		fmt.Fprintf(diff, "\t- %s: %#v\n", name, want[i])
+		fmt.Fprintf(diff, "\t+ %s: %#v\n", name, got[i])
+	}
+
+	return diff.String()
+}
+
+func times(n int) string {
+	if n == 1 {
+		return "1 time"
+	}
+	return fmt.Sprintf("%d times", n)
+}
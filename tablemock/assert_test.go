@@ -0,0 +1,88 @@
+package tablemock_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vitreuz/table-mocks/internal/tbtest"
+	. "github.com/vitreuz/table-mocks/tablemock"
+)
+
+func TestAsserts(t *testing.T) {
+	tests := [...]struct {
+		name   string
+		assert func(testing.TB)
+		expect []string
+	}{
+		{
+			"Called passes",
+			func(t testing.TB) { AssertCalled(t, "Runner.Run", 1) },
+			nil,
+		}, {
+			"Called fails",
+			func(t testing.TB) { AssertCalled(t, "Runner.Run", 0) },
+			[]string{"expected Runner.Run to be called, but it was not"},
+		}, {
+			"Not called fails",
+			func(t testing.TB) { AssertNotCalled(t, "Runner.Run", 2) },
+			[]string{"expected Runner.Run not to be called, but it was called 2 times"},
+		}, {
+			"Called times fails",
+			func(t testing.TB) { AssertCalledTimes(t, "Runner.Run", 1, 3) },
+			[]string{"expected Runner.Run to be called 1 time, but it was called 3 times"},
+		}, {
+			"Called with passes",
+			func(t testing.TB) {
+				AssertCalledWith(t, "Runner.Run", 0, 1,
+					[]string{"distance", "paces"},
+					[]interface{}{1, []string{"a"}},
+					[]interface{}{1, []string{"a"}},
+				)
+			},
+			nil,
+		}, {
+			"Called with missing call",
+			func(t testing.TB) {
+				AssertCalledWith(t, "Runner.Run", 1, 1, nil, nil, nil)
+			},
+			[]string{"expected Runner.Run to be called with args on call 1, but it was called 1 time"},
+		}, {
+			"Called with negative call",
+			func(t testing.TB) {
+				AssertCalledWith(t, "Runner.Run", -1, 1, nil, nil, nil)
+			},
+			[]string{"expected Runner.Run to be called with args on call -1, but calls are counted from 0"},
+		}, {
+			"Called with different args",
+			func(t testing.TB) {
+				AssertCalledWith(t, "Runner.Run", 0, 1,
+					[]string{"distance", "paces"},
+					[]interface{}{1, []string{"a"}},
+					[]interface{}{1, []string{"b"}},
+				)
+			},
+			[]string{strings.Join([]string{
+				"Runner.Run call 0 args do not match (-expected +actual):",
+				`	- paces: []string{"a"}`,
+				`	+ paces: []string{"b"}`,
+				"",
+			}, "\n")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tb := tbtest.New(t)
+			tt.assert(tb)
+
+			if len(tb.Errors) != len(tt.expect) {
+				t.Fatalf("expected %d failures but got %d: %q", len(tt.expect), len(tb.Errors), tb.Errors)
+			}
+			for i := range tt.expect {
+				if tb.Errors[i] != tt.expect[i] {
+					t.Errorf("expected failure %q but got %q", tt.expect[i], tb.Errors[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package tablemock
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/vitreuz/table-mocks/match"
+)
+
+// Call is a single recorded call to a fake method.
+type Call struct {
+	// Seq increases monotonically across every call stored in a Recorder.
+	Seq int
+	// Fake is the fake the call was made on.
+	Fake interface{}
+	// Method is the qualified method name, e.g. "Runner.Run".
+	Method string
+	// Index is the per method call count, matching the key used by ForCall.
+	Index int
+	Args  []interface{}
+}
+
+func (c Call) String() string {
+	args := make([]string, len(c.Args))
+	for i, arg := range c.Args {
+		args[i] = fmt.Sprintf("%#v", arg)
+	}
+	return fmt.Sprintf("%s(%s)", c.Method, strings.Join(args, ", "))
+}
+
+// Recorder keeps the calls made on one or more fakes in the order they
+// happened. A nil Recorder records nothing.
+type Recorder struct {
+	mutex sync.Mutex
+	seq   int
+	calls []Call
+}
+
+// NewRecorder returns an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Record stores a call to method on fake and returns it. It is called by
+// generated fakes.
+func (rec *Recorder) Record(fake interface{}, method string, index int, args ...interface{}) Call {
+	c := Call{Fake: fake, Method: method, Index: index, Args: args}
+	if rec == nil {
+		return c
+	}
+
+	rec.mutex.Lock()
+	c.Seq = rec.seq
+	rec.calls = append(rec.calls, c)
+	rec.seq++
+	rec.mutex.Unlock()
+
+	return c
+}
+
+// Calls returns every recorded call in order.
+func (rec *Recorder) Calls() []Call {
+	if rec == nil {
+		return nil
+	}
+
+	rec.mutex.Lock()
+	calls := append([]Call(nil), rec.calls...)
+	rec.mutex.Unlock()
+
+	return calls
+}
+
+// Reset forgets the calls made on fake, other than those in keep. The kept
+// calls are put back in sequence order and sequence numbers are never reused,
+// so the order of calls across fakes sharing the Recorder stays intact.
+func (rec *Recorder) Reset(fake interface{}, keep ...Call) {
+	if rec == nil {
+		return
+	}
+
+	rec.mutex.Lock()
+	calls := rec.calls[:0]
+	for _, c := range rec.calls {
+		if c.Fake != fake {
+			calls = append(calls, c)
+		}
+	}
+	calls = append(calls, keep...)
+	sort.SliceStable(calls, func(i, j int) bool { return calls[i].Seq < calls[j].Seq })
+	rec.calls = calls
+	rec.mutex.Unlock()
+}
+
+// CallsFor returns the recorded calls made on fake in order.
+func (rec *Recorder) CallsFor(fake interface{}) []Call {
+	var calls []Call
+	for _, c := range rec.Calls() {
+		if c.Fake == fake {
+			calls = append(calls, c)
+		}
+	}
+
+	return calls
+}
+
+// Expected describes a call looked for by the ordering assertions.
+type Expected struct {
+	Fake   interface{}
+	Method string
+	Args   []match.Matcher
+}
+
+// Method expects a call to the qualified method name, e.g. "Store.Open", with
+// arguments accepted by args.
+func Method(name string, args ...match.Matcher) Expected {
+	return Expected{Method: name, Args: args}
+}
+
+// On restricts the expectation to calls made on fake, for when several fakes
+// of the same type share a Recorder.
+func (e Expected) On(fake interface{}) Expected {
+	e.Fake = fake
+	return e
+}
+
+func (e Expected) matches(c Call) bool {
+	if e.Fake != nil && e.Fake != c.Fake {
+		return false
+	}
+	return e.Method == c.Method && match.Args(e.Args, c.Args...)
+}
+
+func (e Expected) String() string {
+	args := make([]string, len(e.Args))
+	for i, arg := range e.Args {
+		args[i] = arg.String()
+	}
+	return fmt.Sprintf("%s(%s)", e.Method, strings.Join(args, ", "))
+}
+
+// AssertExactOrder fails the test unless calls are exactly the expected calls
+// in the same order.
+func AssertExactOrder(t testing.TB, calls []Call, expected ...Expected) {
+	t.Helper()
+
+	ok := len(calls) == len(expected)
+	for i := 0; ok && i < len(calls); i++ {
+		ok = expected[i].matches(calls[i])
+	}
+	if !ok {
+		t.Errorf("calls do not match the expected order:\n%s", orderDiff(calls, expected))
+	}
+}
+
+// AssertOrder fails the test unless the expected calls happened in the given
+// order. Other calls may come before, between or after them.
+func AssertOrder(t testing.TB, calls []Call, expected ...Expected) {
+	t.Helper()
+
+	next := 0
+	for _, c := range calls {
+		if next < len(expected) && expected[next].matches(c) {
+			next++
+		}
+	}
+	if next < len(expected) {
+		t.Errorf("expected %s after %d ordered calls, but it was not found:\n%s",
+			expected[next], next, orderDiff(calls, expected))
+	}
+}
+
+func orderDiff(calls []Call, expected []Expected) string {
+	diff := new(strings.Builder)
+	fmt.Fprintln(diff, "\texpected:")
+	for i, e := range expected {
+		fmt.Fprintf(diff, "\t\t%d: %s\n", i, e)
+	}
+	fmt.Fprintln(diff, "\tactual:")
+	for _, c := range calls {
+		fmt.Fprintf(diff, "\t\t%d: %s\n", c.Seq, c)
+	}
+
+	return diff.String()
+}
@@ -0,0 +1,144 @@
+package tablemock_test
+
+import (
+	"testing"
+
+	"github.com/vitreuz/table-mocks/internal/tbtest"
+	"github.com/vitreuz/table-mocks/match"
+	. "github.com/vitreuz/table-mocks/tablemock"
+)
+
+func TestRecorder(t *testing.T) {
+	first, second := new(int), new(int)
+
+	rec := NewRecorder()
+	rec.Record(first, "Store.Open", 0, "a.txt")
+	rec.Record(second, "Store.Open", 0, "b.txt")
+	rec.Record(first, "Store.Close", 0)
+
+	calls := rec.Calls()
+	if len(calls) != 3 {
+		t.Fatalf("expected 3 calls but got %d", len(calls))
+	}
+	for i, c := range calls {
+		if c.Seq != i {
+			t.Errorf("expected call %d to have seq %d but got %d", i, i, c.Seq)
+		}
+	}
+	if s := calls[0].String(); s != `Store.Open("a.txt")` {
+		t.Errorf("unexpected call string %q", s)
+	}
+
+	own := rec.CallsFor(first)
+	if len(own) != 2 || own[0].Method != "Store.Open" || own[1].Method != "Store.Close" {
+		t.Errorf("unexpected calls for fake: %v", own)
+	}
+
+	var nilRec *Recorder
+	nilRec.Record(first, "Store.Open", 0)
+	if calls := nilRec.Calls(); calls != nil {
+		t.Errorf("expected nil recorder to have no calls but got %v", calls)
+	}
+}
+
+func TestAssertOrder(t *testing.T) {
+	first, second := new(int), new(int)
+
+	rec := NewRecorder()
+	rec.Record(first, "Store.Open", 0, "a.txt")
+	rec.Record(second, "Store.Open", 0, "b.txt")
+	rec.Record(first, "Store.Write", 0, []byte("data"))
+	rec.Record(first, "Store.Close", 0)
+	calls := rec.Calls()
+
+	tests := [...]struct {
+		name   string
+		assert func(testing.TB)
+		fails  bool
+	}{
+		{
+			"Exact order",
+			func(t testing.TB) {
+				AssertExactOrder(t, calls,
+					Method("Store.Open", match.Eq("a.txt")),
+					Method("Store.Open").On(second),
+					Method("Store.Write"),
+					Method("Store.Close"),
+				)
+			},
+			false,
+		}, {
+			"Exact order missing call",
+			func(t testing.TB) {
+				AssertExactOrder(t, calls,
+					Method("Store.Open"),
+					Method("Store.Write"),
+					Method("Store.Close"),
+				)
+			},
+			true,
+		}, {
+			"Partial order",
+			func(t testing.TB) {
+				AssertOrder(t, calls,
+					Method("Store.Open").On(first),
+					Method("Store.Close").On(first),
+				)
+			},
+			false,
+		}, {
+			"Partial order out of order",
+			func(t testing.TB) {
+				AssertOrder(t, calls,
+					Method("Store.Close"),
+					Method("Store.Write"),
+				)
+			},
+			true,
+		}, {
+			"Partial order wrong fake",
+			func(t testing.TB) {
+				AssertOrder(t, calls,
+					Method("Store.Write").On(second),
+				)
+			},
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tb := tbtest.New(t)
+			tt.assert(tb)
+
+			if failed := len(tb.Errors) > 0; failed != tt.fails {
+				t.Errorf("expected failure to be %t but got %t: %q", tt.fails, failed, tb.Errors)
+			}
+		})
+	}
+}
+
+func TestRecorderReset(t *testing.T) {
+	first, second := new(int), new(int)
+
+	rec := NewRecorder()
+	rec.Record(first, "Store.Open", 0)
+	rec.Record(second, "Store.Open", 0)
+	kept := rec.CallsFor(first)
+	rec.Record(first, "Store.Close", 0)
+
+	rec.Reset(first)
+	if calls := rec.CallsFor(first); len(calls) != 0 {
+		t.Errorf("expected no calls after reset but got %v", calls)
+	}
+
+	rec.Reset(first, kept...)
+	calls := rec.Calls()
+	if len(calls) != 2 || calls[0].Fake != first || calls[1].Fake != second {
+		t.Fatalf("expected kept call to be restored in order but got %v", calls)
+	}
+
+	if c := rec.Record(first, "Store.Write", 1); c.Seq != 3 {
+		t.Errorf("expected sequence numbers not to be reused but got %d", c.Seq)
+	}
+}
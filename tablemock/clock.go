@@ -0,0 +1,200 @@
+package tablemock
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Clock is what fakes sleep on to simulate latency. Tests can supply their
+// own, such as a ManualClock, to stay fast and deterministic.
+type Clock interface {
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// Jitter spreads the delays programmed on a fake, returning the delay to
+// sleep for in place of d.
+type Jitter func(d time.Duration) time.Duration
+
+// UniformJitter returns a Jitter moving each delay by up to spread either way,
+// never below zero. Delays follow from seed, so runs can be repeated.
+func UniformJitter(spread time.Duration, seed int64) Jitter {
+	var mutex sync.Mutex
+	random := rand.New(rand.NewSource(seed))
+
+	return func(d time.Duration) time.Duration {
+		if spread <= 0 {
+			return d
+		}
+		mutex.Lock()
+		d += time.Duration(random.Int63n(int64(2*spread)+1)) - spread
+		mutex.Unlock()
+		if d < 0 {
+			return 0
+		}
+		return d
+	}
+}
+
+// Delays holds the delays programmed for single calls of a fake method, by
+// the per method index used by ForCall. They're kept apart from the returns
+// programmed for the calls, so that delaying a call doesn't program it. A
+// Delays is never changed once built, so copies of it can be shared.
+type Delays map[int]time.Duration
+
+// With returns a copy of d that also delays the call with index call.
+func (d Delays) With(call int, delay time.Duration) Delays {
+	delays := make(Delays, len(d)+1)
+	for i, v := range d {
+		delays[i] = v
+	}
+	delays[call] = delay
+
+	return delays
+}
+
+// WithClock makes the fake sleep on c instead of the real clock.
+func WithClock(c Clock) Option {
+	return func(o *Options) {
+		o.clock = c
+	}
+}
+
+// WithJitter makes the fake spread every delay programmed on it with j.
+func WithJitter(j Jitter) Option {
+	return func(o *Options) {
+		o.jitter = j
+	}
+}
+
+// Sleep waits for the delay d, as spread by the fake's Jitter, on the fake's
+// Clock. It returns early with ctx.Err() once ctx is done; a nil ctx waits
+// for the delay alone. Generated fakes call it after releasing their locks.
+func (o Options) Sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	if o.jitter != nil {
+		d = o.jitter(d)
+	}
+
+	var done <-chan struct{}
+	if ctx != nil {
+		done = ctx.Done()
+	}
+	wake := o.clock.After(d)
+	select {
+	case <-wake:
+		return nil
+	case <-done:
+		if c, ok := o.clock.(stopper); ok {
+			c.stop(wake)
+		}
+		return ctx.Err()
+	}
+}
+
+// stopper is a Clock keeping track of its sleepers, which has to be told of
+// a sleep cut short by its ctx.
+type stopper interface {
+	stop(wake <-chan time.Time)
+}
+
+// ManualClock is a Clock whose time only moves when Advance is called.
+type ManualClock struct {
+	mutex    sync.Mutex
+	now      time.Time
+	sleepers []sleeper
+	slept    chan struct{}
+}
+
+type sleeper struct {
+	until time.Time
+	wake  chan time.Time
+}
+
+// NewManualClock returns a ManualClock stopped at now.
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{now: now}
+}
+
+// After returns a channel that receives the clock's time once it has been
+// advanced by d.
+func (c *ManualClock) After(d time.Duration) <-chan time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	wake := make(chan time.Time, 1)
+	if d <= 0 {
+		wake <- c.now
+		return wake
+	}
+	c.sleepers = append(c.sleepers, sleeper{until: c.now.Add(d), wake: wake})
+	if c.slept != nil {
+		close(c.slept)
+		c.slept = nil
+	}
+	return wake
+}
+
+// stop drops the sleeper waiting on wake, so that a sleep cut short is no
+// longer counted by WaitForSleepers.
+func (c *ManualClock) stop(wake <-chan time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for i, s := range c.sleepers {
+		if s.wake == wake {
+			c.sleepers = append(c.sleepers[:i], c.sleepers[i+1:]...)
+			return
+		}
+	}
+}
+
+// Advance moves the clock forward by d, waking every sleeper whose delay has
+// passed.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.now = c.now.Add(d)
+	sleepers := c.sleepers[:0]
+	for _, s := range c.sleepers {
+		if s.until.After(c.now) {
+			sleepers = append(sleepers, s)
+			continue
+		}
+		s.wake <- c.now
+	}
+	c.sleepers = sleepers
+}
+
+// WaitForSleepers waits until at least n callers are sleeping on the clock,
+// or returns ctx.Err() once ctx is done first.
+func (c *ManualClock) WaitForSleepers(ctx context.Context, n int) error {
+	for {
+		c.mutex.Lock()
+		if len(c.sleepers) >= n {
+			c.mutex.Unlock()
+			return nil
+		}
+		if c.slept == nil {
+			c.slept = make(chan struct{})
+		}
+		slept := c.slept
+		c.mutex.Unlock()
+
+		select {
+		case <-slept:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
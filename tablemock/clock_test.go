@@ -0,0 +1,118 @@
+package tablemock_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/vitreuz/table-mocks/tablemock"
+)
+
+func TestSleep(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := [...]struct {
+		name    string
+		opts    []Option
+		ctx     context.Context
+		delay   time.Duration
+		advance time.Duration
+		expect  error
+	}{
+		{"No delay", nil, nil, 0, 0, nil},
+		{"Delay passes", nil, nil, time.Hour, time.Hour, nil},
+		{"Done context", nil, cancelled, time.Hour, 0, context.Canceled},
+		{"Jitter", []Option{WithJitter(func(d time.Duration) time.Duration { return 2 * d })}, nil, time.Hour, 2 * time.Hour, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := NewManualClock(time.Time{})
+			opts := NewOptions(append(tt.opts, WithClock(clock))...)
+
+			slept := make(chan error)
+			go func() { slept <- opts.Sleep(tt.ctx, tt.delay) }()
+
+			if tt.advance > 0 {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+				defer cancel()
+				if err := clock.WaitForSleepers(ctx, 1); err != nil {
+					t.Fatal(err)
+				}
+				clock.Advance(tt.advance - 1)
+				select {
+				case err := <-slept:
+					t.Fatalf("expected to sleep for %s but woke with %v", tt.advance, err)
+				default:
+				}
+				clock.Advance(1)
+			}
+			if err := <-slept; err != tt.expect {
+				t.Errorf("expected %v but got %v", tt.expect, err)
+			}
+		})
+	}
+}
+
+func TestSleepCancelled(t *testing.T) {
+	clock := NewManualClock(time.Time{})
+	opts := NewOptions(WithClock(clock))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	slept := make(chan error)
+	go func() { slept <- opts.Sleep(ctx, time.Hour) }()
+
+	wait, stop := context.WithTimeout(context.Background(), time.Second)
+	defer stop()
+	if err := clock.WaitForSleepers(wait, 1); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	if err := <-slept; err != context.Canceled {
+		t.Fatalf("expected %v but got %v", context.Canceled, err)
+	}
+
+	wait, stop = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer stop()
+	if err := clock.WaitForSleepers(wait, 1); err != context.DeadlineExceeded {
+		t.Errorf("expected the cancelled sleeper to be dropped but waiting for it returned %v", err)
+	}
+}
+
+func TestUniformJitter(t *testing.T) {
+	tests := [...]struct {
+		name   string
+		spread time.Duration
+		delay  time.Duration
+		min    time.Duration
+		max    time.Duration
+	}{
+		{"No spread", 0, time.Second, time.Second, time.Second},
+		{"Spread", 100 * time.Millisecond, time.Second, 900 * time.Millisecond, 1100 * time.Millisecond},
+		{"Never negative", time.Second, 10 * time.Millisecond, 0, 1010 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jitter, again := UniformJitter(tt.spread, 1), UniformJitter(tt.spread, 1)
+			for i := 0; i < 100; i++ {
+				d := jitter(tt.delay)
+				if d < tt.min || d > tt.max {
+					t.Fatalf("expected a delay between %s and %s but got %s", tt.min, tt.max, d)
+				}
+				if repeated := again(tt.delay); repeated != d {
+					t.Fatalf("expected the same seed to give %s but got %s", d, repeated)
+				}
+			}
+		})
+	}
+}
+
+func TestDelaysWith(t *testing.T) {
+	delays := Delays(nil).With(0, time.Second)
+	more := delays.With(1, time.Minute)
+	if len(delays) != 1 || len(more) != 2 || more[0] != time.Second {
+		t.Errorf("expected With to leave the original alone but got %v and %v", delays, more)
+	}
+}
@@ -0,0 +1,31 @@
+package tablemock
+
+import "context"
+
+// HonorContext makes methods taking a leading context.Context return the
+// context's error in their trailing error result once it's done, instead of
+// what was programmed for the call. Without it, only the calls whose context
+// is done while they're blocked or sleeping do so.
+func HonorContext() Option {
+	return func(o *Options) {
+		o.honorContext = true
+	}
+}
+
+// ContextErr returns ctx.Err() when the fake was built with HonorContext, nil
+// otherwise. Generated fakes call it once a call has passed its gate.
+func (o Options) ContextErr(ctx context.Context) error {
+	if !o.honorContext || ctx == nil {
+		return nil
+	}
+	return ctx.Err()
+}
+
+// HasDeadline reports whether ctx carries a deadline. A nil ctx has none.
+func HasDeadline(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	_, ok := ctx.Deadline()
+	return ok
+}
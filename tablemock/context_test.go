@@ -0,0 +1,40 @@
+package tablemock_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/vitreuz/table-mocks/tablemock"
+)
+
+func TestContext(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	deadline, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+
+	tests := [...]struct {
+		name     string
+		opts     []Option
+		ctx      context.Context
+		err      error
+		deadline bool
+	}{
+		{"Ignored by default", nil, cancelled, nil, false},
+		{"Honored when done", []Option{HonorContext()}, cancelled, context.Canceled, false},
+		{"Honored while live", []Option{HonorContext()}, deadline, nil, true},
+		{"Nil context", []Option{HonorContext()}, nil, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := NewOptions(tt.opts...).ContextErr(tt.ctx); err != tt.err {
+				t.Errorf("expected error %v but got %v", tt.err, err)
+			}
+			if ok := HasDeadline(tt.ctx); ok != tt.deadline {
+				t.Errorf("expected deadline %t but got %t", tt.deadline, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package tablemock
+
+import (
+	"context"
+	"sync"
+)
+
+// Gate holds the calls of a fake method while a test keeps it blocked, and
+// lets the test wait for calls to happen without sleeping. The zero Gate is
+// open.
+type Gate struct {
+	mutex   sync.Mutex
+	blocked chan struct{}
+	calls   int
+	counted chan struct{}
+}
+
+// Block makes calls passing the gate wait until Release.
+func (g *Gate) Block() {
+	g.mutex.Lock()
+	if g.blocked == nil {
+		g.blocked = make(chan struct{})
+	}
+	g.mutex.Unlock()
+}
+
+// Release lets every waiting and later call pass.
+func (g *Gate) Release() {
+	g.mutex.Lock()
+	if g.blocked != nil {
+		close(g.blocked)
+		g.blocked = nil
+	}
+	g.mutex.Unlock()
+}
+
+// Pass waits while the gate is blocked. It returns early with ctx.Err() once
+// ctx is done; a nil ctx waits for Release alone.
+func (g *Gate) Pass(ctx context.Context) error {
+	g.mutex.Lock()
+	blocked := g.blocked
+	g.mutex.Unlock()
+	if blocked == nil {
+		return nil
+	}
+
+	var done <-chan struct{}
+	if ctx != nil {
+		done = ctx.Done()
+	}
+	select {
+	case <-blocked:
+		return nil
+	case <-done:
+		return ctx.Err()
+	}
+}
+
+// Count sets the number of calls made so far, waking those waiting for them.
+// Generated fakes call it whenever their call count changes.
+func (g *Gate) Count(calls int) {
+	g.mutex.Lock()
+	g.calls = calls
+	if g.counted != nil {
+		close(g.counted)
+		g.counted = nil
+	}
+	g.mutex.Unlock()
+}
+
+// WaitForCalls waits until at least n calls were made, or returns ctx.Err()
+// once ctx is done first.
+func (g *Gate) WaitForCalls(ctx context.Context, n int) error {
+	for {
+		g.mutex.Lock()
+		if g.calls >= n {
+			g.mutex.Unlock()
+			return nil
+		}
+		if g.counted == nil {
+			g.counted = make(chan struct{})
+		}
+		counted := g.counted
+		g.mutex.Unlock()
+
+		select {
+		case <-counted:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package tablemock_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/vitreuz/table-mocks/tablemock"
+)
+
+func TestGate(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := [...]struct {
+		name   string
+		block  bool
+		ctx    context.Context
+		expect error
+	}{
+		{"Open", false, nil, nil},
+		{"Open with a done context", false, cancelled, nil},
+		{"Blocked until cancelled", true, cancelled, context.Canceled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gate Gate
+			if tt.block {
+				gate.Block()
+			}
+			if err := gate.Pass(tt.ctx); err != tt.expect {
+				t.Errorf("expected %v but got %v", tt.expect, err)
+			}
+		})
+	}
+}
+
+func TestGateRelease(t *testing.T) {
+	var gate Gate
+	gate.Block()
+
+	passed := make(chan error)
+	for i := 0; i < 2; i++ {
+		go func(calls int) {
+			gate.Count(calls)
+			passed <- gate.Pass(nil)
+		}(i + 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := gate.WaitForCalls(ctx, 2); err != nil {
+		t.Fatalf("expected 2 calls but got %v", err)
+	}
+	select {
+	case <-passed:
+		t.Fatal("expected the calls to be blocked")
+	default:
+	}
+
+	gate.Release()
+	for i := 0; i < 2; i++ {
+		if err := <-passed; err != nil {
+			t.Errorf("expected the call to pass but got %v", err)
+		}
+	}
+	if err := gate.Pass(nil); err != nil {
+		t.Errorf("expected the gate to stay open but got %v", err)
+	}
+}
+
+func TestGateWaitForCallsTimeout(t *testing.T) {
+	var gate Gate
+	gate.Count(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := gate.WaitForCalls(ctx, 2); err != context.DeadlineExceeded {
+		t.Errorf("expected %v but got %v", context.DeadlineExceeded, err)
+	}
+}
@@ -0,0 +1,52 @@
+package tablemock
+
+// Every fails every Kth call of a fake method with Err, counting calls from
+// one. The zero Every fails no call.
+type Every struct {
+	K   int
+	Err error
+}
+
+// Fails returns Err and true when the call with the per method index, as used
+// by ForCall, is one of every Kth calls.
+func (e Every) Fails(index int) (error, bool) {
+	if e.K <= 0 {
+		return nil, false
+	}
+	return e.Err, (index+1)%e.K == 0
+}
+
+// Failures holds the errors programmed by FailsOnCall for single calls of a
+// fake method, by the per method index used by ForCall. They're kept apart
+// from the returns programmed for the calls and put over whatever the call
+// ends up returning. A Failures is never changed once built, so copies of it
+// can be shared.
+type Failures map[int]error
+
+// With returns a copy of f that also fails the call with index call.
+func (f Failures) With(call int, err error) Failures {
+	failures := make(Failures, len(f)+1)
+	for i, e := range f {
+		failures[i] = e
+	}
+	failures[call] = err
+
+	return failures
+}
+
+// Panics holds the values programmed by PanicsOnCall for single calls of a
+// fake method to panic with, by the per method index used by ForCall. Like
+// Failures, it's kept apart from the returns programmed for the calls and is
+// never changed once built.
+type Panics map[int]interface{}
+
+// With returns a copy of p that also panics the call with index call.
+func (p Panics) With(call int, value interface{}) Panics {
+	panics := make(Panics, len(p)+1)
+	for i, v := range p {
+		panics[i] = v
+	}
+	panics[call] = value
+
+	return panics
+}
@@ -0,0 +1,52 @@
+package tablemock_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	. "github.com/vitreuz/table-mocks/tablemock"
+)
+
+func TestEvery(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	tests := [...]struct {
+		name   string
+		every  Every
+		expect []bool
+	}{
+		{"Zero", Every{}, []bool{false, false, false, false}},
+		{"Every call", Every{K: 1, Err: errFailed}, []bool{true, true, true, true}},
+		{"Every third call", Every{K: 3, Err: errFailed}, []bool{false, false, true, false, false, true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fails []bool
+			for index := range tt.expect {
+				err, ok := tt.every.Fails(index)
+				if ok && err != errFailed {
+					t.Errorf("expected call %d to fail with %v but got %v", index, errFailed, err)
+				}
+				fails = append(fails, ok)
+			}
+			if !reflect.DeepEqual(fails, tt.expect) {
+				t.Errorf("expected failures %v but got %v", tt.expect, fails)
+			}
+		})
+	}
+}
+
+func TestOnCallWith(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	failures := Failures(nil).With(0, errFailed)
+	if more := failures.With(1, errFailed); len(failures) != 1 || len(more) != 2 {
+		t.Errorf("expected With to leave the original failures alone but got %v and %v", failures, more)
+	}
+	panics := Panics(nil).With(0, "a")
+	if more := panics.With(1, "b"); len(panics) != 1 || len(more) != 2 {
+		t.Errorf("expected With to leave the original panics alone but got %v and %v", panics, more)
+	}
+}
@@ -0,0 +1,41 @@
+package tablemock
+
+// Options holds the settings shared by every method of a generated fake. It
+// is built once by the fake's constructor from the Option values passed to it.
+type Options struct {
+	Recorder *Recorder
+
+	strict       *strict
+	honorContext bool
+	clock        Clock
+	jitter       Jitter
+}
+
+// Option configures a generated fake through its constructor, e.g.
+// NewRunner(tablemock.WithRecorder(rec)).
+type Option func(*Options)
+
+// NewOptions applies opts over the defaults. Unless one is supplied, every fake
+// gets its own Recorder and sleeps on the real clock.
+func NewOptions(opts ...Option) Options {
+	var o Options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.Recorder == nil {
+		o.Recorder = NewRecorder()
+	}
+	if o.clock == nil {
+		o.clock = realClock{}
+	}
+
+	return o
+}
+
+// WithRecorder makes the fake record its calls into rec. Sharing one Recorder
+// between several fakes keeps the order of calls made across all of them.
+func WithRecorder(rec *Recorder) Option {
+	return func(o *Options) {
+		o.Recorder = rec
+	}
+}
@@ -0,0 +1,182 @@
+package tablemock
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+)
+
+// recordedCall is a single call of a recording: the fields of the method's
+// per call struct, e.g. RunnerRunMethod, by name.
+type recordedCall struct {
+	Call   int                        `json:"call"`
+	Fields map[string]json.RawMessage `json:"fields"`
+}
+
+var (
+	errorType     = reflect.TypeOf((*error)(nil)).Elem()
+	contextType   = reflect.TypeOf((*context.Context)(nil)).Elem()
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textType      = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// WriteRecording writes the calls of a fake's methods to w as JSON. methods
+// maps each qualified method name, e.g. "Runner.Run", to its per call structs
+// keyed by call index, e.g. a map[int]RunnerRunMethod. Errors are kept as their
+// messages and contexts are left out. A field that couldn't be read back, such
+// as a func or a chan, fails the recording before anything is written.
+func WriteRecording(w io.Writer, methods map[string]interface{}) error {
+	recording := make(map[string][]recordedCall)
+	for method, calls := range methods {
+		calls := reflect.ValueOf(calls)
+
+		var indices []int
+		for _, key := range calls.MapKeys() {
+			indices = append(indices, int(key.Int()))
+		}
+		sort.Ints(indices)
+
+		recorded := []recordedCall{}
+		for _, index := range indices {
+			fakeMethod := calls.MapIndex(reflect.ValueOf(index))
+
+			fields := make(map[string]json.RawMessage)
+			for i := 0; i < fakeMethod.NumField(); i++ {
+				name := fakeMethod.Type().Field(i).Name
+				if fakeMethod.Field(i).Type() == contextType {
+					continue
+				}
+				raw, err := encodeField(fakeMethod.Field(i))
+				if err != nil {
+					return fmt.Errorf("cannot record %s call %d: field %s %s", method, index, name, err)
+				}
+				fields[name] = raw
+			}
+			recorded = append(recorded, recordedCall{Call: index, Fields: fields})
+		}
+		recording[method] = recorded
+	}
+
+	data, err := json.MarshalIndent(recording, "", "\t")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// ReadRecording reads a recording made by WriteRecording into methods, which
+// maps each qualified method name to the map[int]RunnerRunMethod its calls are
+// added to. Methods the recording doesn't mention are left alone.
+func ReadRecording(r io.Reader, methods map[string]interface{}) error {
+	var recording map[string][]recordedCall
+	if err := json.NewDecoder(r).Decode(&recording); err != nil {
+		return fmt.Errorf("cannot read recording: %s", err)
+	}
+
+	for method, recorded := range recording {
+		calls, ok := methods[method]
+		if !ok {
+			return fmt.Errorf("cannot replay %s: the fake has no such method", method)
+		}
+		callsValue := reflect.ValueOf(calls)
+
+		for _, call := range recorded {
+			fakeMethod := reflect.New(callsValue.Type().Elem()).Elem()
+			for name, raw := range call.Fields {
+				field := fakeMethod.FieldByName(name)
+				if !field.IsValid() {
+					return fmt.Errorf("cannot replay %s call %d: there is no field %s", method, call.Call, name)
+				}
+				if err := decodeField(field, raw); err != nil {
+					return fmt.Errorf("cannot replay %s call %d: field %s %s", method, call.Call, name, err)
+				}
+			}
+			callsValue.SetMapIndex(reflect.ValueOf(call.Call), fakeMethod)
+		}
+	}
+
+	return nil
+}
+
+func encodeField(field reflect.Value) (json.RawMessage, error) {
+	if field.Type() == errorType {
+		if field.IsNil() {
+			return json.RawMessage("null"), nil
+		}
+		return json.Marshal(field.Interface().(error).Error())
+	}
+
+	if err := replayable(field.Type(), make(map[reflect.Type]bool)); err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(field.Interface())
+	if err != nil {
+		return nil, fmt.Errorf("cannot be serialized: %s", err)
+	}
+	return raw, nil
+}
+
+func decodeField(field reflect.Value, raw json.RawMessage) error {
+	if field.Type() == errorType {
+		var msg *string
+		if err := json.Unmarshal(raw, &msg); err != nil {
+			return err
+		}
+		if msg != nil {
+			field.Set(reflect.ValueOf(errors.New(*msg)))
+		}
+		return nil
+	}
+
+	if err := replayable(field.Type(), make(map[reflect.Type]bool)); err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, field.Addr().Interface())
+}
+
+// replayable returns an error for types that values can't be read back into
+// from JSON: funcs, chans, unsafe pointers, complex numbers and any interface
+// but the empty one. Only the fields of a per call struct may be errors.
+func replayable(typ reflect.Type, seen map[reflect.Type]bool) error {
+	if seen[typ] {
+		return nil
+	}
+	seen[typ] = true
+
+	if typ.Implements(marshalerType) || reflect.PtrTo(typ).Implements(marshalerType) ||
+		typ.Implements(textType) || reflect.PtrTo(typ).Implements(textType) {
+		return nil
+	}
+
+	switch typ.Kind() {
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer, reflect.Complex64, reflect.Complex128:
+		return fmt.Errorf("holds a %s, which cannot be serialized", typ)
+	case reflect.Interface:
+		if typ.NumMethod() > 0 {
+			return fmt.Errorf("holds the interface %s, which cannot be replayed", typ)
+		}
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return replayable(typ.Elem(), seen)
+	case reflect.Map:
+		if err := replayable(typ.Key(), seen); err != nil {
+			return err
+		}
+		return replayable(typ.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < typ.NumField(); i++ {
+			if f := typ.Field(i); f.PkgPath == "" {
+				if err := replayable(f.Type, seen); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
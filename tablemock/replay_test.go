@@ -0,0 +1,140 @@
+package tablemock_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/vitreuz/table-mocks/tablemock"
+)
+
+type runnerRunMethod struct {
+	CtxArg         context.Context
+	DistanceArg    string
+	PacesArg       []int
+	DurationResult time.Duration
+	ErrResult      error
+}
+
+type runnerWatchMethod struct {
+	DoneArg   chan struct{}
+	StopArg   func()
+	ErrResult error
+}
+
+type runnerWalkMethod struct {
+	ReaderArg interface{ Read([]byte) (int, error) }
+}
+
+func TestRecording(t *testing.T) {
+	runs := map[int]runnerRunMethod{
+		0: {CtxArg: context.Background(), DistanceArg: "5k", PacesArg: []int{4, 5}, DurationResult: time.Minute},
+		1: {DistanceArg: "10k", ErrResult: errors.New("too far")},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteRecording(&buf, map[string]interface{}{"Runner.Run": runs}); err != nil {
+		t.Fatal(err)
+	}
+	recording := buf.String()
+	if strings.Contains(recording, "CtxArg") {
+		t.Errorf("expected the context to be left out of\n%s", recording)
+	}
+	run := runs[0]
+	run.CtxArg = nil
+	runs[0] = run
+
+	replayed := make(map[int]runnerRunMethod)
+	if err := ReadRecording(strings.NewReader(recording), map[string]interface{}{"Runner.Run": replayed}); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(replayed, runs) {
+		t.Errorf("expected to replay %v but got %v", runs, replayed)
+	}
+
+	var again bytes.Buffer
+	if err := WriteRecording(&again, map[string]interface{}{"Runner.Run": replayed}); err != nil {
+		t.Fatal(err)
+	}
+	if again.String() != recording {
+		t.Errorf("expected the replay to record\n%s\nbut got\n%s", recording, again.String())
+	}
+}
+
+func TestWriteRecordingErrors(t *testing.T) {
+	tests := [...]struct {
+		name    string
+		methods map[string]interface{}
+		expect  string
+	}{
+		{
+			"Chan arg",
+			map[string]interface{}{"Runner.Watch": map[int]runnerWatchMethod{0: {}}},
+			"cannot record Runner.Watch call 0: field DoneArg holds a chan struct {}, which cannot be serialized",
+		}, {
+			"Interface arg",
+			map[string]interface{}{"Runner.Walk": map[int]runnerWalkMethod{3: {}}},
+			"cannot record Runner.Walk call 3: field ReaderArg holds the interface interface { Read([]uint8) (int, error) }, which cannot be replayed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := WriteRecording(&buf, tt.methods)
+			if err == nil || err.Error() != tt.expect {
+				t.Errorf("expected error %q but got %v", tt.expect, err)
+			}
+			if buf.Len() != 0 {
+				t.Errorf("expected nothing to be written but got %q", buf.String())
+			}
+		})
+	}
+}
+
+func TestReadRecordingErrors(t *testing.T) {
+	tests := [...]struct {
+		name      string
+		recording string
+		expect    string
+	}{
+		{
+			"Not JSON",
+			`Runner.Run`,
+			"cannot read recording: invalid character 'R' looking for beginning of value",
+		}, {
+			"Unknown method",
+			`{"Runner.Jog": [{"call": 0, "fields": {}}]}`,
+			"cannot replay Runner.Jog: the fake has no such method",
+		}, {
+			"Unknown field",
+			`{"Runner.Run": [{"call": 1, "fields": {"SpeedArg": 3}}]}`,
+			"cannot replay Runner.Run call 1: there is no field SpeedArg",
+		}, {
+			"Wrong type",
+			`{"Runner.Run": [{"call": 2, "fields": {"DistanceArg": 5}}]}`,
+			"cannot replay Runner.Run call 2: field DistanceArg json: cannot unmarshal number into Go value of type string",
+		}, {
+			"Func arg",
+			`{"Runner.Watch": [{"call": 0, "fields": {"StopArg": null}}]}`,
+			"cannot replay Runner.Watch call 0: field StopArg holds a func(), which cannot be serialized",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			methods := map[string]interface{}{
+				"Runner.Run":   make(map[int]runnerRunMethod),
+				"Runner.Watch": make(map[int]runnerWatchMethod),
+			}
+			err := ReadRecording(strings.NewReader(tt.recording), methods)
+			if err == nil || err.Error() != tt.expect {
+				t.Errorf("expected error %q but got %v", tt.expect, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package tablemock
+
+import "errors"
+
+// ErrSequenceEnded is the error returned by calls made past the end of a
+// sequence of returns that ends with FailAfter.
+var ErrSequenceEnded = errors.New("tablemock: the sequence of returns has ended")
+
+// SequenceEnd is what the calls made past the end of a sequence of returns
+// get.
+type SequenceEnd int
+
+const (
+	// RepeatLast gives every later call the last returns of the sequence.
+	RepeatLast SequenceEnd = iota
+	// Cycle starts the sequence over.
+	Cycle
+	// FailAfter fails every later call with ErrSequenceEnded, for methods with
+	// a trailing error result. Calls to other methods get nothing programmed.
+	FailAfter
+)
+
+// Sequence is the sequence of returns programmed on the first Len calls of
+// a fake method. The zero Sequence holds no returns.
+//
+// A sequence shares the per method indexes of ForCall: its returns are those
+// ForCall would program for calls 0 to Len-1, so a later ForCall for one of
+// those calls changes that step of the sequence, while a ForCall made before
+// the sequence is overwritten by it. A new sequence drops every step of the
+// one it replaces. Calls past the end get the returns picked by End unless
+// ForCall programmed them on their own.
+type Sequence struct {
+	Len int
+	End SequenceEnd
+}
+
+// Index returns the per method index, as used by ForCall, of the returns the
+// call with index call gets.
+func (s Sequence) Index(call int) int {
+	if s.Len <= 0 || call < s.Len {
+		return call
+	}
+	switch s.End {
+	case RepeatLast:
+		return s.Len - 1
+	case Cycle:
+		return call % s.Len
+	}
+	return call
+}
+
+// Fails returns ErrSequenceEnded and true when the call with index call is
+// made past the end of a sequence ending with FailAfter.
+func (s Sequence) Fails(call int) (error, bool) {
+	if s.Len <= 0 || call < s.Len || s.End != FailAfter {
+		return nil, false
+	}
+	return ErrSequenceEnded, true
+}
@@ -0,0 +1,60 @@
+package tablemock_test
+
+import (
+	"reflect"
+	"testing"
+
+	. "github.com/vitreuz/table-mocks/tablemock"
+)
+
+func TestSequence(t *testing.T) {
+	tests := [...]struct {
+		name    string
+		seq     Sequence
+		indices []int
+		fails   []bool
+	}{
+		{
+			"Zero",
+			Sequence{},
+			[]int{0, 1, 2, 3},
+			[]bool{false, false, false, false},
+		}, {
+			"Repeat last",
+			Sequence{Len: 2, End: RepeatLast},
+			[]int{0, 1, 1, 1},
+			[]bool{false, false, false, false},
+		}, {
+			"Cycle",
+			Sequence{Len: 2, End: Cycle},
+			[]int{0, 1, 0, 1, 0},
+			[]bool{false, false, false, false, false},
+		}, {
+			"Fail after",
+			Sequence{Len: 2, End: FailAfter},
+			[]int{0, 1, 2, 3},
+			[]bool{false, false, true, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var indices []int
+			var fails []bool
+			for call := range tt.indices {
+				indices = append(indices, tt.seq.Index(call))
+				err, ok := tt.seq.Fails(call)
+				if ok && err != ErrSequenceEnded {
+					t.Errorf("expected call %d to fail with %v but got %v", call, ErrSequenceEnded, err)
+				}
+				fails = append(fails, ok)
+			}
+			if !reflect.DeepEqual(indices, tt.indices) {
+				t.Errorf("expected indices %v but got %v", tt.indices, indices)
+			}
+			if !reflect.DeepEqual(fails, tt.fails) {
+				t.Errorf("expected failures %v but got %v", tt.fails, fails)
+			}
+		})
+	}
+}
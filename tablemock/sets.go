@@ -0,0 +1,75 @@
+package tablemock
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Sets holds the values a fake method copies into the memory its pointer
+// args point to, by arg index. The args of a variadic method are indexed as
+// if they were passed one by one. A Sets is never changed once built, so
+// copies of it can be shared.
+type Sets map[int]interface{}
+
+// With returns a copy of s that also sets the arg at index n to value.
+func (s Sets) With(n int, value interface{}) Sets {
+	sets := make(Sets, len(s)+1)
+	for i, v := range s {
+		sets[i] = v
+	}
+	sets[n] = value
+
+	return sets
+}
+
+// Apply copies every value of s into the arg at its index. It panics when an
+// arg is missing, isn't a non-nil pointer or can't hold its value, since
+// that's a mistake in the test rather than in the code under test.
+func (s Sets) Apply(args ...interface{}) {
+	for n, value := range s {
+		if n < 0 || n >= len(args) {
+			panic(fmt.Sprintf("tablemock: cannot set arg %d of a call with %d args", n, len(args)))
+		}
+		if err := setArg(args[n], value); err != nil {
+			panic(fmt.Sprintf("tablemock: cannot set arg %d: %s", n, err))
+		}
+	}
+}
+
+// ApplyVariadic is Apply for a variadic method, whose last arg is the slice
+// of its variadic args.
+func (s Sets) ApplyVariadic(args ...interface{}) {
+	if len(s) == 0 || len(args) == 0 {
+		return
+	}
+
+	variadic := reflect.ValueOf(args[len(args)-1])
+	args = args[:len(args)-1]
+	for i := 0; i < variadic.Len(); i++ {
+		args = append(args, variadic.Index(i).Interface())
+	}
+	s.Apply(args...)
+}
+
+func setArg(arg, value interface{}) error {
+	dst := reflect.ValueOf(arg)
+	if dst.Kind() != reflect.Ptr || dst.IsNil() {
+		return fmt.Errorf("%T is not a non-nil pointer", arg)
+	}
+	elem := dst.Elem()
+	if value == nil {
+		elem.Set(reflect.Zero(elem.Type()))
+		return nil
+	}
+
+	src := reflect.ValueOf(value)
+	if !src.Type().AssignableTo(elem.Type()) && src.Kind() == reflect.Ptr && !src.IsNil() {
+		src = src.Elem()
+	}
+	if !src.Type().AssignableTo(elem.Type()) {
+		return fmt.Errorf("a %T cannot be stored in a %T", value, arg)
+	}
+	elem.Set(src)
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package tablemock_test
+
+import (
+	"testing"
+
+	. "github.com/vitreuz/table-mocks/tablemock"
+)
+
+type user struct {
+	ID   int
+	Name string
+}
+
+func TestSets(t *testing.T) {
+	tests := [...]struct {
+		name   string
+		sets   Sets
+		apply  func(Sets, *user, *string)
+		user   user
+		str    string
+		panics string
+	}{
+		{
+			"Nothing",
+			nil,
+			func(s Sets, u *user, str *string) { s.Apply(u, str) },
+			user{}, "",
+			"",
+		}, {
+			"Values",
+			Sets(nil).With(0, user{ID: 1}).With(1, "name"),
+			func(s Sets, u *user, str *string) { s.Apply(u, str) },
+			user{ID: 1}, "name",
+			"",
+		}, {
+			"Pointer value",
+			Sets(nil).With(0, &user{ID: 2}),
+			func(s Sets, u *user, str *string) { s.Apply(u, str) },
+			user{ID: 2}, "",
+			"",
+		}, {
+			"Into interface args",
+			Sets(nil).With(1, "name"),
+			func(s Sets, u *user, str *string) { s.Apply(interface{}(u), interface{}(str)) },
+			user{}, "name",
+			"",
+		}, {
+			"Variadic",
+			Sets(nil).With(1, "name"),
+			func(s Sets, u *user, str *string) { s.ApplyVariadic(u, []interface{}{str}) },
+			user{}, "name",
+			"",
+		}, {
+			"Missing arg",
+			Sets(nil).With(2, "name"),
+			func(s Sets, u *user, str *string) { s.Apply(u, str) },
+			user{}, "",
+			"tablemock: cannot set arg 2 of a call with 2 args",
+		}, {
+			"Not a pointer",
+			Sets(nil).With(0, "name"),
+			func(s Sets, u *user, str *string) { s.Apply(*str) },
+			user{}, "",
+			"tablemock: cannot set arg 0: string is not a non-nil pointer",
+		}, {
+			"Wrong type",
+			Sets(nil).With(1, 5),
+			func(s Sets, u *user, str *string) { s.Apply(u, str) },
+			user{}, "",
+			"tablemock: cannot set arg 1: a int cannot be stored in a *string",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var u user
+			var str string
+			func() {
+				defer func() {
+					r := recover()
+					if r == nil {
+						r = ""
+					}
+					if r != tt.panics {
+						t.Errorf("expected panic %q but got %v", tt.panics, r)
+					}
+				}()
+				tt.apply(tt.sets, &u, &str)
+			}()
+			if u != tt.user || str != tt.str {
+				t.Errorf("expected %v and %q but got %v and %q", tt.user, tt.str, u, str)
+			}
+		})
+	}
+}
+
+func TestSetsWith(t *testing.T) {
+	sets := Sets(nil).With(0, "a")
+	more := sets.With(1, "b")
+	if len(sets) != 1 || len(more) != 2 {
+		t.Errorf("expected With to leave the original alone but got %v and %v", sets, more)
+	}
+}
@@ -0,0 +1,71 @@
+package tablemock
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+type strict struct {
+	t         testing.TB
+	goroutine uint64
+
+	mutex    sync.Mutex
+	failures []string
+}
+
+// Strict makes the fake fail t whenever a method is called on a call index
+// with no configured return or stub. Calls made from the goroutine that called
+// Strict stop the test through t.Fatalf. Calls from any other goroutine cannot
+// do so safely, so their failures are recorded and reported once the test
+// finishes.
+func Strict(t testing.TB) Option {
+	s := &strict{t: t, goroutine: goroutineID()}
+	t.Cleanup(s.report)
+
+	return func(o *Options) {
+		o.strict = s
+	}
+}
+
+// Unexpected reports c as a call nobody programmed. It does nothing unless the
+// fake was built with Strict. Generated fakes call it after releasing their
+// locks, since t.Fatalf never returns.
+func (o Options) Unexpected(c Call) {
+	s := o.strict
+	if s == nil {
+		return
+	}
+	s.t.Helper()
+
+	if goroutineID() == s.goroutine {
+		s.t.Fatalf("unexpected call %s on call %d", c, c.Index)
+	}
+
+	s.mutex.Lock()
+	s.failures = append(s.failures, "unexpected call "+c.String()+" on call "+strconv.Itoa(c.Index))
+	s.mutex.Unlock()
+}
+
+func (s *strict) report() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, failure := range s.failures {
+		s.t.Errorf("%s (from another goroutine)", failure)
+	}
+}
+
+// goroutineID parses the current goroutine's id out of its stack header,
+// which always starts with "goroutine <id> [".
+func goroutineID() uint64 {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	buf = bytes.TrimPrefix(buf, []byte("goroutine "))
+	buf = buf[:bytes.IndexByte(buf, ' ')]
+
+	id, _ := strconv.ParseUint(string(buf), 10, 64)
+	return id
+}
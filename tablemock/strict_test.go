@@ -0,0 +1,78 @@
+package tablemock_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/vitreuz/table-mocks/internal/tbtest"
+	. "github.com/vitreuz/table-mocks/tablemock"
+)
+
+func TestStrict(t *testing.T) {
+	unexpected := Call{Method: "Runner.Run", Index: 1, Args: []interface{}{5}}
+
+	tests := [...]struct {
+		name   string
+		test   func(testing.TB)
+		errors []string
+		fatals []string
+	}{
+		{
+			"Lenient by default",
+			func(t testing.TB) {
+				NewOptions().Unexpected(unexpected)
+			},
+			nil,
+			nil,
+		}, {
+			"Strict on the test goroutine",
+			func(t testing.TB) {
+				NewOptions(Strict(t)).Unexpected(unexpected)
+				t.Errorf("not reached")
+			},
+			nil,
+			[]string{"unexpected call Runner.Run(5) on call 1"},
+		}, {
+			"Strict on another goroutine",
+			func(t testing.TB) {
+				opts := NewOptions(Strict(t))
+
+				var wg sync.WaitGroup
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					opts.Unexpected(unexpected)
+				}()
+				wg.Wait()
+			},
+			[]string{"unexpected call Runner.Run(5) on call 1 (from another goroutine)"},
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tb := tbtest.New(t)
+			tb.Run(tt.test)
+
+			if !equalStrings(tb.Errors, tt.errors) {
+				t.Errorf("expected errors %q but got %q", tt.errors, tb.Errors)
+			}
+			if !equalStrings(tb.Fatals, tt.fatals) {
+				t.Errorf("expected fatals %q but got %q", tt.fatals, tb.Fatals)
+			}
+		})
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}